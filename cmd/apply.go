@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// applyResult is the outcome of one applied action. Secret is set for
+// created keys.
+type applyResult struct {
+	Action manifest.Action `json:"action"`
+	Secret string          `json:"secret,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// applyOutput is the JSON output of apply
+type applyOutput struct {
+	Plan    *manifest.Plan `json:"plan"`
+	Results []applyResult  `json:"results"`
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make the proxy match YAML manifests",
+	Long: `Plan the changes needed to match YAML manifests, as plan does, and make
+them after confirmation. Users are created before memberships and teams before
+keys; deletions, which require --prune, run last in the reverse order. Apply
+stops at the first failure, since later changes may depend on it.
+
+Secrets of newly created keys are printed once and cannot be shown again.
+See plan --help for the manifest format.
+
+Example:
+  navigatorctl apply -f manifests/
+
+  # Also delete what was removed from the manifests, without a prompt
+  navigatorctl apply -f manifests/ --prune --yes`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		plan := loadPlan(cmd)
+		output := applyOutput{Plan: plan, Results: []applyResult{}}
+
+		if format == "table" {
+			printPlan(plan)
+		}
+		if len(plan.Actions) == 0 {
+			if format == "json" {
+				printApplyOutput(output)
+			}
+			return
+		}
+		requireConfirmation(yes, fmt.Sprintf("Apply %d changes?", len(plan.Actions)))
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+		applied, secrets := 0, 0
+		for _, action := range plan.Actions {
+			secret, err := action.Apply(ctx, client)
+			if err != nil && ctx.Err() != nil {
+				handleError("Error applying manifests", err)
+			}
+
+			result := applyResult{Action: action, Secret: secret}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			output.Results = append(output.Results, result)
+
+			if format == "table" {
+				switch {
+				case err != nil:
+					fmt.Fprintf(os.Stderr, "%s: failed: %v\n", action, err)
+				case secret != "":
+					fmt.Printf("%s: done, secret %s\n", action, secret)
+				default:
+					fmt.Printf("%s: done\n", action)
+				}
+			}
+			if err != nil {
+				break
+			}
+			applied++
+			if secret != "" {
+				secrets++
+			}
+		}
+
+		switch format {
+		case "json":
+			printApplyOutput(output)
+		case "table":
+			fmt.Printf("Applied %d of %d changes\n", applied, len(plan.Actions))
+			if secrets > 0 {
+				fmt.Println("Store the new key secrets securely; they cannot be shown again.")
+			}
+		}
+		if applied < len(plan.Actions) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	addManifestFlags(applyCmd)
+	applyCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+func printApplyOutput(output applyOutput) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
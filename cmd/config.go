@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the navigatorctl config file",
+	Long: `Config commands allow you to:
+- Create the config file interactively
+- View the effective settings, with API keys redacted
+- Get and set single settings, keeping the file's comments
+- Check the configuration and the connection to the proxy
+- Switch between contexts, named sets of settings for each proxy
+
+Contexts are defined in the config file:
+
+  current-context: dev
+  contexts:
+    dev:
+      api:
+        url: https://dev.example.com
+        key: sk-dev-key
+    prod:
+      api:
+        url: https://prod.example.com
+        key: sk-prod-key
+
+Settings of the current context override the top-level ones; flags and
+NAVIGATOR_* environment variables override both. --context, or
+NAVIGATOR_CONTEXT, selects another context for a single command. Commands
+that change the proxy print the active context first.
+
+Examples:
+  # Create the config file and check that it works
+  navigatorctl config init
+  navigatorctl config validate
+
+  # Show and change settings
+  navigatorctl config view
+  navigatorctl config set api.timeout 60s
+
+  # List contexts; the active one is marked with *
+  navigatorctl config get-contexts
+
+  # Switch to prod for every following command
+  navigatorctl config use-context prod
+
+  # Run a single command against staging
+  navigatorctl team list --context staging`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context NAME",
+	Short: "Set the current context in the config file",
+	Long: `Set current-context in the config file, so that following commands use the
+settings of the named context. The rest of the file, including comments, is
+left as it is.
+
+Example:
+  navigatorctl config use-context prod`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Config keys are not case sensitive; store the name as it is listed
+		name := strings.ToLower(args[0])
+
+		file := viper.ConfigFileUsed()
+		if file == "" {
+			fmt.Fprintln(os.Stderr, "Error: no config file found; create $HOME/.navigatorctl.yaml or use --config")
+			os.Exit(1)
+		}
+		settings, err := config.Settings(viper.GetViper(), name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SetValue(file, []string{config.CurrentContextKey}, name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating config file: %v\n", err)
+			os.Exit(1)
+		}
+
+		sub := viper.New()
+		sub.MergeConfigMap(settings)
+		if url := sub.GetString("api.url"); url != "" {
+			fmt.Printf("Switched to context %q (%s)\n", name, url)
+			return
+		}
+		fmt.Printf("Switched to context %q\n", name)
+	},
+}
+
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List the contexts in the config file",
+	Long: `List the contexts defined in the config file with their API URLs. The
+active context, from --context or current-context, is marked with *.
+
+Example:
+  navigatorctl config get-contexts
+  navigatorctl config get-contexts --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+		contexts := config.Contexts(viper.GetViper(), activeContext)
+
+		switch format {
+		case "json":
+			if contexts == nil {
+				contexts = []config.Context{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(contexts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			if len(contexts) == 0 {
+				fmt.Println("No contexts defined in the config file")
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Current", "Name", "API URL"})
+			for _, c := range contexts {
+				current := ""
+				if c.Current {
+					current = "*"
+				}
+				table.Append([]string{current, c.Name, c.URL})
+			}
+			table.Render()
+		}
+	},
+}
+
+var configCurrentContextCmd = &cobra.Command{
+	Use:   "current-context",
+	Short: "Print the active context",
+	Long: `Print the name of the active context: the one given with --context or
+NAVIGATOR_CONTEXT, or else current-context from the config file.
+
+Example:
+  navigatorctl config current-context`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if activeContext == "" {
+			fmt.Fprintln(os.Stderr, "Error: no context is set; use 'navigatorctl config use-context NAME'")
+			os.Exit(1)
+		}
+		if contextErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", contextErr)
+			os.Exit(1)
+		}
+		fmt.Println(activeContext)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configCurrentContextCmd)
+
+	configGetContextsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
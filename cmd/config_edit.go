@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the effective configuration",
+	Long: `Show the settings commands run with: the config file with the active
+context applied, overridden by environment variables and flags, and defaults
+for everything else. API keys are redacted.
+
+Example:
+  navigatorctl config view
+  navigatorctl config view --context prod --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("output")
+		if format != "yaml" && format != "json" {
+			fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'yaml' or 'json'\n", format)
+			os.Exit(1)
+		}
+		if contextErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", contextErr)
+			os.Exit(1)
+		}
+
+		settings := config.View(viper.GetViper())
+		// The --context flag is bound for lookups, not a setting
+		delete(settings, "context")
+
+		if err := printValue(settings, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding settings: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print the effective value of a setting",
+	Long: `Print the value commands use for a dotted key, after the active context,
+environment variables and flags are applied. Single values are printed as they
+are, so they can be used in scripts; sections are printed as YAML.
+
+Example:
+  navigatorctl config get api.url
+  navigatorctl config get retry`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if contextErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", contextErr)
+			os.Exit(1)
+		}
+
+		value, ok := config.Get(viper.GetViper(), args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: %s is not set\n", args[0])
+			os.Exit(1)
+		}
+		if err := printValue(value, "yaml"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding value: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Set a value in the config file",
+	Long: `Set a dotted key in the config file in use, or in $HOME/.navigatorctl.yaml
+when there is none, creating the file and sections as needed. Comments and
+the order of keys are kept. URLs and durations are checked before they are
+written.
+
+Example:
+  navigatorctl config set api.url https://ai.example.com
+  navigatorctl config set retry.max_retries 5
+
+  # Settings of a context
+  navigatorctl config set contexts.prod.api.key sk-prod-key`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+
+		keys := strings.Split(key, ".")
+		for _, k := range keys {
+			if k == "" {
+				fmt.Fprintf(os.Stderr, "Error: invalid key %q\n", key)
+				os.Exit(1)
+			}
+		}
+		if err := validateSetting(keys, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid value for %s: %v\n", key, err)
+			os.Exit(1)
+		}
+
+		file := configFilePath()
+		if err := config.SetValue(file, keys, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating config file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Set %s in %s\n", key, file)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+
+	configViewCmd.Flags().StringP("output", "o", "yaml", "Output format (yaml, json)")
+}
+
+// configFilePath returns the config file to write: the one in use, or
+// $HOME/.navigatorctl.yaml when none was found
+func configFilePath() string {
+	if file := viper.ConfigFileUsed(); file != "" {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return filepath.Join(home, ".navigatorctl.yaml")
+}
+
+// validateSetting checks values of settings with a known format, including
+// those of contexts
+func validateSetting(keys []string, value string) error {
+	name := keys[len(keys)-1]
+	if len(keys) >= 2 {
+		name = keys[len(keys)-2] + "." + name
+	}
+
+	switch name {
+	case "api.url":
+		return config.ValidateURL(value)
+	case "api.timeout", "retry.initial_backoff", "retry.max_backoff":
+		_, err := time.ParseDuration(value)
+		return err
+	case "output.format":
+		if value != "table" && value != "json" {
+			return fmt.Errorf("must be 'table' or 'json'")
+		}
+	}
+	return nil
+}
+
+// printValue prints single values as they are and anything else as YAML or
+// JSON
+func printValue(value interface{}, format string) error {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, []string:
+	default:
+		if format == "yaml" {
+			fmt.Println(value)
+			return nil
+		}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
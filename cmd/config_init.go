@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ncecere/navigatorctl/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create or update the config file interactively",
+	Long: `Ask for the API URL and key and write them to the config file in use, or
+to $HOME/.navigatorctl.yaml. A missing file is created from a commented
+template with the default settings; in an existing file only the URL and key
+change. Current values are offered as defaults.
+
+With --context the values are written to that context instead, and it becomes
+the current context when none is set. Without a terminal, --api-url and
+--api-key must both be given.
+
+Example:
+  navigatorctl config init
+
+  # Add a context for the production proxy
+  navigatorctl config init --context prod
+
+  # Without prompts
+  navigatorctl config init --api-url https://ai.example.com --api-key sk-...`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file := configFilePath()
+		contextName := viper.GetString("context")
+
+		// A new context starts empty instead of copying the top-level values
+		url, key := viper.GetString("api.url"), viper.GetString("api.key")
+		if contextErr != nil {
+			url, _ = cmd.Flags().GetString("api-url")
+			key, _ = cmd.Flags().GetString("api-key")
+		}
+
+		if !cmd.Flags().Changed("api-url") || !cmd.Flags().Changed("api-key") {
+			if !isTerminal(os.Stdin) {
+				fmt.Fprintln(os.Stderr, "Error: stdin is not a terminal; use --api-url and --api-key")
+				os.Exit(1)
+			}
+			if !cmd.Flags().Changed("api-url") {
+				url = promptDefault("API URL", url, url)
+			}
+			if !cmd.Flags().Changed("api-key") {
+				key = promptDefault("API key", key, maskKey(key))
+			}
+		}
+		if err := config.ValidateURL(url); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid API URL: %v\n", err)
+			os.Exit(1)
+		}
+		if key == "" {
+			fmt.Fprintln(os.Stderr, "Error: an API key is required")
+			os.Exit(1)
+		}
+
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			err = os.MkdirAll(filepath.Dir(file), 0755)
+			if err == nil {
+				err = os.WriteFile(file, config.DefaultFile, 0600)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating config file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		set := func(value string, keys ...string) {
+			if err := config.SetValue(file, keys, value); err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating config file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if contextName == "" {
+			set(url, "api", "url")
+			set(key, "api", "key")
+		} else {
+			set(url, config.ContextsKey, contextName, "api", "url")
+			set(key, config.ContextsKey, contextName, "api", "key")
+			if viper.GetString(config.CurrentContextKey) == "" {
+				set(contextName, config.CurrentContextKey)
+			}
+		}
+
+		if contextName != "" {
+			fmt.Printf("Wrote context %q to %s\n", contextName, file)
+		} else {
+			fmt.Printf("Wrote %s\n", file)
+		}
+		fmt.Println("Check the connection with: navigatorctl config validate")
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configInitCmd)
+}
+
+// promptDefault asks for a value, returning current when the answer is empty.
+// shown is how current is displayed, e.g. masked.
+func promptDefault(question, current, shown string) string {
+	if shown != "" {
+		question += " [" + shown + "]"
+	}
+	if answer := prompt(question + ": "); answer != "" {
+		return answer
+	}
+	return current
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Statuses of a configuration check
+const (
+	checkOK      = "ok"
+	checkFailed  = "failed"
+	checkSkipped = "skipped"
+)
+
+// configCheck is the result of one configuration check
+type configCheck struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// configValidateOutput is the JSON output of config validate
+type configValidateOutput struct {
+	Valid  bool          `json:"valid"`
+	Checks []configCheck `json:"checks"`
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration and the connection to the proxy",
+	Long: `Check that the config file can be read, that the active context exists,
+that api.url is an http or https URL, that the proxy answers its liveness
+endpoint and that it accepts api.key. Checks that depend on a failed one are
+skipped. The exit status is 1 when any check fails.
+
+Example:
+  navigatorctl config validate
+  navigatorctl config validate --context prod`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+		checks := validateConfig(cmd)
+
+		output := configValidateOutput{Valid: true, Checks: checks}
+		for _, check := range checks {
+			if check.Status == checkFailed {
+				output.Valid = false
+			}
+		}
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Check", "Status", "Detail"})
+			table.SetAutoWrapText(false)
+			for _, check := range checks {
+				table.Append([]string{check.Check, check.Status, check.Detail})
+			}
+			table.Render()
+			if output.Valid {
+				fmt.Println("Configuration is valid")
+			}
+		}
+
+		if !output.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+
+	configValidateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// validateConfig runs the checks in order, skipping those whose prerequisites
+// failed
+func validateConfig(cmd *cobra.Command) []configCheck {
+	var checks []configCheck
+	add := func(name, status, format string, args ...interface{}) bool {
+		checks = append(checks, configCheck{Check: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+		return status == checkOK
+	}
+
+	switch file := viper.ConfigFileUsed(); {
+	case configErr != nil:
+		add("config file", checkFailed, "%v", configErr)
+	case file == "":
+		add("config file", checkOK, "none found; using flags and environment variables")
+	case !fileExists(file):
+		add("config file", checkFailed, "%s does not exist", file)
+	default:
+		add("config file", checkOK, "%s", file)
+	}
+
+	if activeContext != "" {
+		if contextErr != nil {
+			add("context", checkFailed, "%v", contextErr)
+		} else {
+			add("context", checkOK, "%s", activeContext)
+		}
+	}
+
+	urlOK := false
+	url := viper.GetString("api.url")
+	switch urlErr := config.ValidateURL(url); {
+	case url == "":
+		add("api.url", checkFailed, "not set")
+	case urlErr != nil:
+		add("api.url", checkFailed, "%v", urlErr)
+	default:
+		urlOK = add("api.url", checkOK, "%s", url)
+	}
+
+	keyOK := false
+	if key := viper.GetString("api.key"); key == "" {
+		add("api.key", checkFailed, "not set")
+	} else {
+		keyOK = add("api.key", checkOK, "%s", maskKey(key))
+	}
+
+	if !urlOK {
+		add("proxy reachable", checkSkipped, "api.url is invalid")
+		add("key accepted", checkSkipped, "api.url is invalid")
+		return checks
+	}
+
+	ctx := cmd.Context()
+	client := newAPIClient()
+	start := time.Now()
+	err := client.CheckLivenessContext(ctx)
+	if ctx.Err() != nil {
+		handleError("Error validating configuration", err)
+	}
+	var apiErr *api.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		add("proxy reachable", checkFailed, "%s answered %d; is it a LiteLLM proxy?", apiErr.Path, apiErr.StatusCode)
+		add("key accepted", checkSkipped, "the proxy is not reachable")
+		return checks
+	case err != nil:
+		add("proxy reachable", checkFailed, "%v", err)
+		add("key accepted", checkSkipped, "the proxy is not reachable")
+		return checks
+	default:
+		add("proxy reachable", checkOK, "alive (%s)", time.Since(start).Round(time.Millisecond))
+	}
+
+	if !keyOK {
+		add("key accepted", checkSkipped, "api.key is not set")
+		return checks
+	}
+	models, err := client.ListModelsContext(ctx)
+	if ctx.Err() != nil {
+		handleError("Error validating configuration", err)
+	}
+	switch {
+	case api.IsUnauthorized(err) || api.IsForbidden(err):
+		add("key accepted", checkFailed, "the proxy rejected the key: %v", err)
+	case err != nil:
+		add("key accepted", checkFailed, "%v", err)
+	default:
+		add("key accepted", checkOK, "%d models available", len(models.Data))
+	}
+	return checks
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses of drift
+const (
+	exitCodeNoDrift = 0
+	exitCodeDrift   = 2
+)
+
+// driftReport is the JSON report of drift
+type driftReport struct {
+	Drifted bool             `json:"drifted"`
+	Checks  []manifest.Check `json:"checks"`
+}
+
+// JUnit XML report of drift: one test case per object, failing when it
+// drifted
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Detect changes made to the proxy outside of the manifests",
+	Long: `Compare YAML manifests with the proxy and report every object that drifted:
+settings that differ, objects that are missing, members added or removed and
+managed objects no manifest describes. The manifest format is described in
+plan --help.
+
+The exit status is 0 without drift, 2 with drift and 1 on errors, so CI
+pipelines can fail on drift. Reports are printed as a table, as JSON or as
+JUnit XML with one test case per object; --report-file writes the report to a
+new file readable only by you instead of stdout. Existing files are never
+overwritten.
+
+Example:
+  navigatorctl drift -f manifests/
+
+  # In CI, with a JUnit report for the test results page
+  navigatorctl drift -f manifests/ --output junit --report-file drift.xml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, _ := cmd.Flags().GetStringSlice("file")
+		format, _ := cmd.Flags().GetString("output")
+		reportFile, _ := cmd.Flags().GetString("report-file")
+
+		if format != "table" && format != "json" && format != "junit" {
+			fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'table', 'json' or 'junit'\n", format)
+			os.Exit(1)
+		}
+
+		set, err := manifest.Load(files...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading manifests: %v\n", err)
+			os.Exit(1)
+		}
+
+		client := getAPIClient()
+		state, err := manifest.Fetch(cmd.Context(), client, set)
+		if err != nil {
+			handleError("Error reading live state", err)
+		}
+
+		checks, err := manifest.DetectDrift(set, state)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		drifted := 0
+		for _, check := range checks {
+			if check.Drifted() {
+				drifted++
+			}
+		}
+
+		var report bytes.Buffer
+		switch format {
+		case "json":
+			err = writeDriftJSON(&report, checks, drifted > 0)
+		case "junit":
+			err = writeDriftJUnit(&report, checks, drifted)
+		case "table":
+			writeDriftTable(&report, checks, drifted)
+		}
+		if err == nil && reportFile != "" {
+			err = writePrivateFile(reportFile, report.Bytes())
+		} else if err == nil {
+			_, err = report.WriteTo(os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+		if reportFile != "" {
+			fmt.Printf("%d of %d objects drifted; report written to %s\n", drifted, len(checks), reportFile)
+		}
+
+		if drifted > 0 {
+			os.Exit(exitCodeDrift)
+		}
+		os.Exit(exitCodeNoDrift)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+
+	driftCmd.Flags().StringSliceP("file", "f", nil, "Manifest file or directory (repeatable)")
+	driftCmd.Flags().StringP("output", "o", "table", "Report format (table, json, junit)")
+	driftCmd.Flags().String("report-file", "", "Write the report to this new file (mode 0600) instead of stdout")
+	driftCmd.MarkFlagRequired("file")
+}
+
+func writeDriftTable(w io.Writer, checks []manifest.Check, drifted int) {
+	for _, check := range checks {
+		if !check.Drifted() {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", check.Kind, check.Name, check.Status)
+		for _, change := range check.Changes {
+			fmt.Fprintln(w, "    "+change.String())
+		}
+	}
+
+	if drifted == 0 {
+		fmt.Fprintf(w, "No drift: all %d objects match the manifests\n", len(checks))
+		return
+	}
+	fmt.Fprintf(w, "\nDrift in %d of %d objects\n", drifted, len(checks))
+}
+
+func writeDriftJSON(w io.Writer, checks []manifest.Check, drifted bool) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(driftReport{Drifted: drifted, Checks: checks})
+}
+
+func writeDriftJUnit(w io.Writer, checks []manifest.Check, drifted int) error {
+	suite := junitTestSuite{Name: "drift", Tests: len(checks), Failures: drifted}
+	for _, check := range checks {
+		testCase := junitTestCase{ClassName: check.Kind, Name: check.Name}
+		if check.Drifted() {
+			var lines []string
+			for _, change := range check.Changes {
+				lines = append(lines, change.String())
+			}
+			message := fmt.Sprintf("%s %s is %s", check.Kind, check.Name, check.Status)
+			if check.Status == manifest.StatusChanged {
+				noun := "fields"
+				if len(check.Changes) == 1 {
+					noun = "field"
+				}
+				message = fmt.Sprintf("%s %s has %d drifted %s", check.Kind, check.Name, len(check.Changes), noun)
+			}
+			testCase.Failure = &junitFailure{Message: message, Type: check.Status, Text: strings.Join(lines, "\n")}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	report := junitTestSuites{
+		Name:     "navigatorctl drift",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// exportOutput is the JSON output of export
+type exportOutput struct {
+	Dir      string   `json:"dir"`
+	Written  []string `json:"written"`
+	Removed  []string `json:"removed"`
+	Warnings []string `json:"warnings"`
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export teams, members, users and keys as YAML manifests",
+	Long: `Snapshot the proxy into a directory of manifests that plan and apply read:
+teams/<team>.yaml holds each team with its members and keys, users.yaml every
+user and keys.yaml the keys outside any team.
+
+Key secrets are never exported, and metadata values whose names look secret
+(token, password, api_key, ...) are written as <redacted>; plan keeps their
+live values. Documents, members, models and metadata are sorted, so exporting
+an unchanged proxy rewrites identical files and the directory can be diffed
+in git. Files under teams/ for teams that no longer exist are removed.
+
+Keys without an alias, or sharing their alias with another key, cannot be
+named in a manifest and are skipped with a warning.
+
+Example:
+  navigatorctl export --dir manifests/
+
+  # Nightly: catch changes made outside of git
+  navigatorctl export --dir manifests/ && git -C manifests diff --exit-code`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		format := getOutputFormat(cmd)
+
+		client := getAPIClient()
+		state, err := manifest.Snapshot(cmd.Context(), client)
+		if err != nil {
+			handleError("Error reading live state", err)
+		}
+		files, warnings := manifest.Export(state)
+
+		output := exportOutput{Dir: dir, Written: []string{}, Removed: []string{}, Warnings: warnings}
+		if output.Warnings == nil {
+			output.Warnings = []string{}
+		}
+
+		written := make(map[string]bool)
+		for _, file := range files {
+			data, err := file.Bytes()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding manifests: %v\n", err)
+				os.Exit(1)
+			}
+			changed, err := writeIfChanged(filepath.Join(dir, file.Name), data)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing manifests: %v\n", err)
+				os.Exit(1)
+			}
+			written[file.Name] = true
+			if changed {
+				output.Written = append(output.Written, file.Name)
+			}
+		}
+
+		removed, err := removeStaleManifests(dir, written)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing old manifests: %v\n", err)
+			os.Exit(1)
+		}
+		output.Removed = removed
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			for _, name := range output.Written {
+				fmt.Println("  wrote " + name)
+			}
+			for _, name := range output.Removed {
+				fmt.Println("  removed " + name)
+			}
+			fmt.Printf("Exported %d teams, %d users and %d keys to %s (%d files changed)\n",
+				len(state.Teams), len(state.Users), len(state.Keys)-len(warnings), dir,
+				len(output.Written)+len(output.Removed))
+			for _, warning := range warnings {
+				fmt.Fprintln(os.Stderr, "Warning: "+warning)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("dir", "d", "", "Directory to write the manifests to")
+	exportCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	exportCmd.MarkFlagRequired("dir")
+}
+
+// writeIfChanged writes data to path unless the file already holds it, and
+// reports whether it wrote
+func writeIfChanged(path string, data []byte) (bool, error) {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, data, 0644)
+}
+
+// removeStaleManifests deletes the team files and keys.yaml a previous export
+// wrote that the current one did not. Other files are left alone.
+func removeStaleManifests(dir string, written map[string]bool) ([]string, error) {
+	candidates, err := filepath.Glob(filepath.Join(dir, "teams", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, filepath.Join(dir, "keys.yaml"))
+
+	removed := []string{}
+	for _, path := range candidates {
+		name := filepath.ToSlash(strings.TrimPrefix(path, filepath.Clean(dir)+string(filepath.Separator)))
+		if written[name] {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
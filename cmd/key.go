@@ -9,7 +9,7 @@ import (
 var keyCmd = &cobra.Command{
 	Use:   "key",
 	Short: "Manage API keys",
-	Long:  "List and get information about API keys.",
+	Long:  "Generate, list, inspect, update, rotate, transfer, block and delete API keys.",
 }
 
 func init() {
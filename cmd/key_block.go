@@ -0,0 +1,99 @@
+// cmd/key_block.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	keyBlockCmd = &cobra.Command{
+		Use:   "block [KEY...]",
+		Short: "Block API keys",
+		Long: `Block one or more API keys so the proxy rejects requests made with them.
+Blocked keys keep their settings and spend and can be unblocked later. Keys can
+be given as secrets (sk-...), hashed tokens or aliases, as arguments or one per
+line on stdin ("-").
+
+Example:
+  # Block a compromised key
+  navigatorctl key block leaked-ci-bot
+
+  # Show which keys would be blocked
+  navigatorctl key block - --dry-run < suspicious-keys.txt`,
+		Annotations: mutating,
+		Run: func(cmd *cobra.Command, args []string) {
+			runKeyBlock(cmd, args, true)
+		},
+	}
+
+	keyUnblockCmd = &cobra.Command{
+		Use:   "unblock [KEY...]",
+		Short: "Unblock API keys",
+		Long: `Unblock one or more previously blocked API keys. Keys can be given as
+secrets (sk-...), hashed tokens or aliases, as arguments or one per line on
+stdin ("-").
+
+Example:
+  navigatorctl key unblock leaked-ci-bot --yes`,
+		Annotations: mutating,
+		Run: func(cmd *cobra.Command, args []string) {
+			runKeyBlock(cmd, args, false)
+		},
+	}
+)
+
+func init() {
+	for _, c := range []*cobra.Command{keyBlockCmd, keyUnblockCmd} {
+		c.Flags().Bool("dry-run", false, "Show the keys that would be affected without changing them")
+		c.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+		keyCmd.AddCommand(c)
+	}
+}
+
+// runKeyBlock blocks or unblocks every key given on the command line,
+// continuing past failures and exiting non-zero if any key failed
+func runKeyBlock(cmd *cobra.Command, args []string, block bool) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	client := getAPIClient()
+	keys := resolveKeyArgs(cmd.Context(), client, args)
+
+	verb, apply := "unblock", client.UnblockKeyContext
+	if block {
+		verb, apply = "block", client.BlockKeyContext
+	}
+
+	if dryRun {
+		fmt.Printf("The following %d keys would be %sed:\n", len(keys), verb)
+		printKeyTable(keys)
+		return
+	}
+
+	fmt.Printf("The following %d keys will be %sed:\n", len(keys), verb)
+	printKeyTable(keys)
+	requireConfirmation(yes, fmt.Sprintf("%s %d keys?", strings.ToUpper(verb[:1])+verb[1:], len(keys)))
+
+	failed := 0
+	for _, key := range keys {
+		if _, err := apply(cmd.Context(), key.Token); err != nil {
+			if cmd.Context().Err() != nil {
+				handleError(fmt.Sprintf("Error %sing keys", verb), err)
+			}
+			fmt.Fprintf(os.Stderr, "Error %sing key %s: %v\n", verb, maskKey(key.KeyName), err)
+			failed++
+			continue
+		}
+		fmt.Printf("Successfully %sed key %s (%s)\n", verb, maskKey(key.KeyName), getOrDefault(key.KeyAlias, "-"))
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Failed to %s %d of %d keys\n", verb, failed, len(keys))
+		os.Exit(1)
+	}
+}
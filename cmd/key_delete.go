@@ -0,0 +1,111 @@
+// cmd/key_delete.go
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var keyDeleteCmd = &cobra.Command{
+	Use:   "delete [KEY...]",
+	Short: "Delete API keys",
+	Long: `Delete one or more API keys. Keys can be given as secrets (sk-...),
+hashed tokens or aliases, as arguments or one per line on stdin ("-").
+
+The keys are shown before anything is deleted and you are asked to confirm.
+
+Example:
+  # Delete by alias, asking for confirmation
+  navigatorctl key delete old-ci-bot
+
+  # Show what would be deleted without changing anything
+  navigatorctl key delete old-ci-bot 5d2176316f4e07efe8edcab574708e28a6c8ffe51569beb2d4808a9e9f970d70 --dry-run
+
+  # Delete a list of keys from a file without prompting
+  navigatorctl key delete - --yes < orphaned-keys.txt`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client := getAPIClient()
+		keys := resolveKeyArgs(cmd.Context(), client, args)
+
+		if dryRun {
+			fmt.Printf("The following %d keys would be deleted:\n", len(keys))
+			printKeyTable(keys)
+			return
+		}
+
+		fmt.Printf("The following %d keys will be deleted:\n", len(keys))
+		printKeyTable(keys)
+		requireConfirmation(yes, fmt.Sprintf("Delete %d keys? This cannot be undone.", len(keys)))
+
+		tokens := make([]string, len(keys))
+		for i, key := range keys {
+			tokens[i] = key.Token
+		}
+
+		result, err := client.DeleteKeysContext(cmd.Context(), tokens)
+		if err != nil {
+			handleError("Error deleting keys", err)
+		}
+
+		fmt.Printf("Successfully deleted %d keys\n", len(result.DeletedKeys))
+	},
+}
+
+func init() {
+	keyDeleteCmd.Flags().Bool("dry-run", false, "Show the keys that would be deleted without deleting them")
+	keyDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	keyCmd.AddCommand(keyDeleteCmd)
+}
+
+// resolveKeyArgs looks up every key given as an argument, or on stdin when
+// the only argument is "-". It exits if no keys were given or any key cannot
+// be found, so no action is taken on a partial list.
+func resolveKeyArgs(ctx context.Context, client *api.Client, args []string) []api.KeyObject {
+	identifiers := args
+	if len(args) == 1 && args[0] == "-" {
+		lines, err := readLines(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading keys from stdin: %v\n", err)
+			os.Exit(1)
+		}
+		identifiers = lines
+	}
+
+	if len(identifiers) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one key (secret, hashed token or alias) is required")
+		os.Exit(1)
+	}
+
+	var keys []api.KeyObject
+	seen := make(map[string]bool)
+	failed := false
+	for _, identifier := range identifiers {
+		key, err := client.ResolveKeyContext(ctx, identifier)
+		if err != nil {
+			if ctx.Err() != nil {
+				handleError("Error resolving keys", err)
+			}
+			fmt.Fprintf(os.Stderr, "Error resolving key %s: %v\n", identifier, err)
+			failed = true
+			continue
+		}
+		if !seen[key.Token] {
+			seen[key.Token] = true
+			keys = append(keys, *key)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	return keys
+}
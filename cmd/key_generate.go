@@ -0,0 +1,132 @@
+// cmd/key_generate.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var keyGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a new API key",
+	Long: `Generate a new API key. The secret is printed exactly once; store it
+immediately or use --secret-file to write it to a file readable only by you.
+
+Example:
+  # Team key limited to two models
+  navigatorctl key generate --alias ci-bot --team CHAT --models gpt-4.1,gpt-4.1-mini
+
+  # Personal key with a monthly budget that expires in 90 days
+  navigatorctl key generate --alias jdoe-laptop --user-id jdoe --max-budget 25 --budget-duration 30d --duration 90d
+
+  # Write the secret to a file instead of the terminal
+  navigatorctl key generate --alias deploy --team CHAT --metadata env=prod --secret-file ./deploy.key`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		secretFile, _ := cmd.Flags().GetString("secret-file")
+
+		request := api.GenerateKeyRequest{}
+		request.KeyAlias, _ = cmd.Flags().GetString("alias")
+		request.TeamID, _ = cmd.Flags().GetString("team")
+		request.UserID, _ = cmd.Flags().GetString("user-id")
+		request.Models, _ = cmd.Flags().GetStringSlice("models")
+		request.BudgetDuration, _ = cmd.Flags().GetString("budget-duration")
+		request.Duration, _ = cmd.Flags().GetString("duration")
+		if cmd.Flags().Changed("max-budget") {
+			maxBudget, _ := cmd.Flags().GetFloat64("max-budget")
+			request.MaxBudget = &maxBudget
+		}
+		if cmd.Flags().Changed("tpm-limit") {
+			tpmLimit, _ := cmd.Flags().GetInt64("tpm-limit")
+			request.TPMLimit = &tpmLimit
+		}
+		if cmd.Flags().Changed("rpm-limit") {
+			rpmLimit, _ := cmd.Flags().GetInt64("rpm-limit")
+			request.RPMLimit = &rpmLimit
+		}
+		request.Metadata = getMetadataFlag(cmd, "metadata")
+
+		client := getAPIClient()
+		result, err := client.GenerateKeyContext(cmd.Context(), request)
+		if err != nil {
+			handleError("Error generating key", err)
+		}
+
+		secret := result.Key
+		if secretFile != "" {
+			if err := writePrivateFile(secretFile, []byte(secret+"\n")); err != nil {
+				// The key exists but its secret is about to be lost; show it
+				// rather than failing silently.
+				fmt.Fprintf(os.Stderr, "Error writing secret file: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Secret (shown once): %s\n", secret)
+				os.Exit(1)
+			}
+			result.Key = ""
+		}
+
+		switch output {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Println("Key generated:")
+			fmt.Println("Name:   ", result.KeyName)
+			fmt.Println("Alias:  ", getOrDefault(result.KeyAlias, "-"))
+			fmt.Println("Team:   ", getOrDefault(result.TeamID, "-"))
+			fmt.Println("User:   ", getOrDefault(result.UserID, "-"))
+			fmt.Println("Models: ", getOrDefault(strings.Join(result.Models, ", "), "all-team-models"))
+			fmt.Println("Expires:", getOrDefault(result.Expires, "never"))
+			if secretFile == "" {
+				fmt.Println()
+				fmt.Println("Secret:", secret)
+				fmt.Println("This secret will not be shown again. Store it somewhere safe now.")
+			}
+		}
+
+		if secretFile != "" {
+			fmt.Fprintf(os.Stderr, "Secret written to %s\n", secretFile)
+		}
+	},
+}
+
+func init() {
+	keyGenerateCmd.Flags().String("alias", "", "Human readable alias for the key")
+	keyGenerateCmd.Flags().String("team", "", "Team ID or alias that owns the key")
+	keyGenerateCmd.Flags().String("user-id", "", "User ID that owns the key")
+	keyGenerateCmd.Flags().StringSlice("models", nil, "Models the key may use (default: all team models)")
+	keyGenerateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	keyGenerateCmd.Flags().String("budget-duration", "", "Budget reset period, e.g. 30d, 1h")
+	keyGenerateCmd.Flags().Int64("tpm-limit", 0, "Tokens per minute limit")
+	keyGenerateCmd.Flags().Int64("rpm-limit", 0, "Requests per minute limit")
+	keyGenerateCmd.Flags().String("duration", "", "Time until the key expires, e.g. 90d (default: never)")
+	keyGenerateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs")
+	keyGenerateCmd.Flags().String("secret-file", "", "Write the secret to this file (mode 0600) instead of printing it")
+	keyGenerateCmd.Flags().String("output", "table", "Output format: table or json")
+	keyCmd.AddCommand(keyGenerateCmd)
+}
+
+// getMetadataFlag converts a key=value flag into a metadata map, or nil if the
+// flag was not given
+func getMetadataFlag(cmd *cobra.Command, name string) map[string]interface{} {
+	values, _ := cmd.Flags().GetStringToString(name)
+	if len(values) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		metadata[k] = v
+	}
+	return metadata
+}
@@ -3,109 +3,79 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"io"
 	"os"
 
+	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/spf13/cobra"
 )
 
-type KeyListResponse struct {
-	Keys        []string `json:"keys"`
-	TotalCount  int      `json:"total_count"`
-	CurrentPage int      `json:"current_page"`
-	TotalPages  int      `json:"total_pages"`
-}
-
 var keyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List API keys",
 	Run: func(cmd *cobra.Command, args []string) {
-		apiURL, _ := cmd.Flags().GetString("api-url")
-		apiKey, _ := cmd.Flags().GetString("api-key")
-		if apiURL == "" || apiKey == "" {
-			fmt.Fprintln(os.Stderr, "API URL and API Key are required")
-			os.Exit(1)
-		}
+		page, pageSize, all := getPagination(cmd)
 
-		url := fmt.Sprintf("%s/key/list?page=1&size=100&return_full_object=true&include_team_keys=true&sort_order=desc", apiURL)
-		req, err := http.NewRequest("GET", url, nil)
+		client := getAPIClient()
+		result, err := client.ListKeysContext(cmd.Context(), api.KeyListOptions{
+			Page:     page,
+			PageSize: pageSize,
+			AllPages: all,
+		})
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to create request:", err)
-			os.Exit(1)
+			handleError("Error listing keys", err)
 		}
-		req.Header.Set("accept", "application/json")
-		req.Header.Set("x-litellm-api-key", apiKey)
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Request failed:", err)
-			os.Exit(1)
-		}
-		defer resp.Body.Close()
+		printKeyTable(result.Keys)
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := ioutil.ReadAll(resp.Body)
-			fmt.Fprintf(os.Stderr, "API error: %s\n%s\n", resp.Status, string(body))
-			os.Exit(1)
+		if !all {
+			printPageHint(page, result.TotalPages, result.TotalCount)
 		}
+	},
+}
 
-		type KeyObject struct {
-			Token     string   `json:"token"`
-			KeyName   string   `json:"key_name"`
-			KeyAlias  string   `json:"key_alias"`
-			Spend     float64  `json:"spend"`
-			Models    []string `json:"models"`
-			TeamID    *string  `json:"team_id"`
-			CreatedAt string   `json:"created_at"`
-		}
+func init() {
+	addPaginationFlags(keyListCmd)
+	keyCmd.AddCommand(keyListCmd)
+}
 
-		type KeyListFullResponse struct {
-			Keys        []KeyObject `json:"keys"`
-			TotalCount  int         `json:"total_count"`
-			CurrentPage int         `json:"current_page"`
-			TotalPages  int         `json:"total_pages"`
-		}
+// printKeyTable prints keys as a table matching the user keys output, with
+// key names masked
+func printKeyTable(keys []api.KeyObject) {
+	printKeyTableTo(os.Stdout, keys)
+}
 
-		var fullResult KeyListFullResponse
-		if err := json.NewDecoder(resp.Body).Decode(&fullResult); err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to decode response:", err)
-			os.Exit(1)
+// printKeyTableTo prints the key table to w
+func printKeyTableTo(w io.Writer, keys []api.KeyObject) {
+	fmt.Fprintf(w, "  %-10s | %-10s | %-4s | %-7s | %-15s | %s\n", "KEY NAME", "ALIAS", "TEAM", "SPEND", "MODELS", "CREATED")
+	fmt.Fprintln(w, "-------------+------------+------+---------+-----------------+----------------------")
+	for _, key := range keys {
+		alias := key.KeyAlias
+		if alias == "" {
+			alias = "-"
 		}
-
-		// Output as table matching user keys output
-		fmt.Printf("  %-10s | %-10s | %-4s | %-7s | %-15s | %s\n", "KEY NAME", "ALIAS", "TEAM", "SPEND", "MODELS", "CREATED")
-		fmt.Println("-------------+------------+------+---------+-----------------+----------------------")
-		for _, key := range fullResult.Keys {
-			// Mask key name as sk-...Oktg
-			masked := key.KeyName
-			if len(masked) > 8 {
-				masked = masked[:2] + "-..." + masked[len(masked)-4:]
-			}
-			alias := key.KeyAlias
-			if alias == "" {
-				alias = "-"
-			}
-			team := "-"
-			if key.TeamID != nil && *key.TeamID != "" {
-				team = *key.TeamID
-			}
-			spend := fmt.Sprintf("$%.2f", key.Spend)
-			models := "-"
-			if len(key.Models) > 0 {
-				models = key.Models[0]
-			}
-			created := key.CreatedAt
-			if len(created) > 19 {
-				created = created[:19]
-			}
-			fmt.Printf("  %-10s | %-10s | %-4s | %-7s | %-15s | %s\n", masked, alias, team, spend, models, created)
+		team := "-"
+		if key.TeamID != "" {
+			team = key.TeamID
 		}
-	},
+		spend := fmt.Sprintf("$%.2f", key.Spend)
+		models := "-"
+		if len(key.Models) > 0 {
+			models = key.Models[0]
+		}
+		created := key.CreatedAt
+		if len(created) > 19 {
+			created = created[:19]
+		}
+		fmt.Fprintf(w, "  %-10s | %-10s | %-4s | %-7s | %-15s | %s\n", maskKey(key.KeyName), alias, team, spend, models, created)
+	}
 }
 
-func init() {
-	keyCmd.AddCommand(keyListCmd)
+// maskKey shortens a key name for display, e.g. sk-...Oktg
+func maskKey(name string) string {
+	if len(name) > 8 {
+		return name[:2] + "-..." + name[len(name)-4:]
+	}
+	return name
 }
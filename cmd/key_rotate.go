@@ -0,0 +1,237 @@
+// cmd/key_rotate.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// keyRotateFailure records a key that could not be rotated. Result is set
+// when the new key was created but the old one could not be retired, so its
+// secret is not lost.
+type keyRotateFailure struct {
+	Token    string               `json:"token"`
+	KeyAlias string               `json:"key_alias,omitempty"`
+	Error    string               `json:"error"`
+	Result   *api.RotateKeyResult `json:"result,omitempty"`
+}
+
+// keyRotateOutput is the JSON envelope printed by key rotate
+type keyRotateOutput struct {
+	RotatedAt string                 `json:"rotated_at"`
+	Rotated   []*api.RotateKeyResult `json:"rotated"`
+	Failed    []keyRotateFailure     `json:"failed"`
+}
+
+var keyRotateCmd = &cobra.Command{
+	Use:   "rotate [KEY...]",
+	Short: "Rotate API keys",
+	Long: `Issue new secrets for API keys, keeping their alias, team, models, budgets,
+limits and metadata. Keys can be given as secrets (sk-...), hashed tokens or
+aliases, as arguments or one per line on stdin ("-"), or selected with --team.
+--older-than limits rotation to keys not rotated within that period (e.g. 90d,
+2w, 36h); the last rotation is recorded in the key's metadata as rotated_at.
+
+By default the old secret stops working immediately. With --grace-period the
+old key is renamed to <alias>-rotated-<timestamp> and keeps working until the
+grace period ends, so deployments can switch over.
+
+Use --output json for a machine readable envelope with the new secrets. The
+list of keys and the confirmation prompt go to stderr.
+
+Example:
+  # Rotate a single key
+  navigatorctl key rotate ci-bot
+
+  # Rotate every CHAT key older than 90 days, keeping old keys for a day
+  navigatorctl key rotate --team CHAT --older-than 90d --grace-period 24h --yes --output json > rotated.json
+
+  # Show which keys are due for rotation
+  navigatorctl key rotate --team CHAT --older-than 90d --dry-run`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		team, _ := cmd.Flags().GetString("team")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		gracePeriod, _ := cmd.Flags().GetString("grace-period")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		output, _ := cmd.Flags().GetString("output")
+
+		if team != "" && len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: give either keys or --team, not both")
+			os.Exit(1)
+		}
+
+		var maxAge time.Duration
+		if olderThan != "" {
+			var err error
+			if maxAge, err = parseAge(olderThan); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --older-than: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		client := getAPIClient()
+		var keys []api.KeyObject
+		if team != "" {
+			result, err := client.ListTeamKeysContext(cmd.Context(), team, api.KeyListOptions{AllPages: true})
+			if err != nil {
+				handleError("Error listing team keys", err)
+			}
+			if len(result.Warnings) > 0 {
+				for _, warning := range result.Warnings {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", warning)
+				}
+				fmt.Fprintln(os.Stderr, "Error: not all team keys could be read; nothing was rotated")
+				os.Exit(1)
+			}
+			for _, key := range result.Keys {
+				keys = append(keys, api.KeyObject{Token: key.Key, KeyInfo: key.Info})
+			}
+		} else {
+			keys = resolveKeyArgs(cmd.Context(), client, args)
+		}
+
+		now := time.Now()
+		if maxAge > 0 {
+			due := keys[:0]
+			for _, key := range keys {
+				rotated, err := api.LastRotated(key.KeyInfo)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: skipping key %s: %v\n", maskKey(key.KeyName), err)
+					continue
+				}
+				if now.Sub(rotated) >= maxAge {
+					due = append(due, key)
+				}
+			}
+			keys = due
+		}
+
+		if len(keys) == 0 {
+			fmt.Fprintln(os.Stderr, "No keys to rotate")
+			if output == "json" {
+				printRotateOutput(keyRotateOutput{RotatedAt: now.UTC().Format(time.RFC3339), Rotated: []*api.RotateKeyResult{}, Failed: []keyRotateFailure{}})
+			}
+			return
+		}
+
+		if dryRun {
+			fmt.Fprintf(os.Stderr, "The following %d keys would be rotated:\n", len(keys))
+			printKeyTableTo(os.Stderr, keys)
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "The following %d keys will be rotated:\n", len(keys))
+		printKeyTableTo(os.Stderr, keys)
+		question := fmt.Sprintf("Rotate %d keys? The old secrets stop working immediately.", len(keys))
+		if gracePeriod != "" {
+			question = fmt.Sprintf("Rotate %d keys? The old secrets stop working after %s.", len(keys), gracePeriod)
+		}
+		requireConfirmation(yes, question)
+
+		result := keyRotateOutput{
+			RotatedAt: now.UTC().Format(time.RFC3339),
+			Rotated:   []*api.RotateKeyResult{},
+			Failed:    []keyRotateFailure{},
+		}
+		opts := api.RotateKeyOptions{GracePeriod: gracePeriod, Now: now}
+		for _, key := range keys {
+			rotated, err := client.RotateKeyContext(cmd.Context(), key, opts)
+			if err != nil {
+				result.Failed = append(result.Failed, keyRotateFailure{
+					Token:    key.Token,
+					KeyAlias: key.KeyAlias,
+					Error:    err.Error(),
+					Result:   rotated,
+				})
+				fmt.Fprintf(os.Stderr, "Error rotating key %s: %v\n", maskKey(key.KeyName), err)
+				if cmd.Context().Err() != nil {
+					// Print what was rotated so the new secrets are not lost
+					break
+				}
+				continue
+			}
+			result.Rotated = append(result.Rotated, rotated)
+		}
+
+		if output == "json" {
+			printRotateOutput(result)
+		} else {
+			printRotateTable(result)
+		}
+
+		if cmd.Context().Err() != nil {
+			handleError("Error rotating keys", cmd.Context().Err())
+		}
+		if len(result.Failed) > 0 {
+			fmt.Fprintf(os.Stderr, "Failed to rotate %d of %d keys\n", len(result.Failed), len(keys))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	keyRotateCmd.Flags().String("team", "", "Rotate the keys of this team ID or alias")
+	keyRotateCmd.Flags().String("older-than", "", "Only rotate keys not rotated within this period, e.g. 90d, 2w, 36h")
+	keyRotateCmd.Flags().String("grace-period", "", "Keep old keys working for this long, e.g. 24h, 7d")
+	keyRotateCmd.Flags().Bool("dry-run", false, "Show the keys that would be rotated without rotating them")
+	keyRotateCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	keyRotateCmd.Flags().String("output", "table", "Output format: table or json")
+	keyCmd.AddCommand(keyRotateCmd)
+}
+
+func printRotateOutput(result keyRotateOutput) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printRotateTable(result keyRotateOutput) {
+	results := result.Rotated
+	for _, failure := range result.Failed {
+		if failure.Result != nil && failure.Result.Secret != "" {
+			results = append(results, failure.Result)
+		}
+	}
+
+	for _, rotated := range results {
+		fmt.Printf("Rotated key %s (%s) via %s\n", getOrDefault(rotated.KeyAlias, "-"), getOrDefault(rotated.TeamID, "-"), rotated.Method)
+		fmt.Println("  Secret: ", rotated.Secret)
+		if rotated.OldKeyExpires != "" {
+			fmt.Printf("  Old key %s expires %s\n", getOrDefault(rotated.OldKeyAlias, "-"), rotated.OldKeyExpires)
+		}
+	}
+	if len(results) > 0 {
+		fmt.Println("These secrets will not be shown again. Store them somewhere safe now.")
+	}
+}
+
+// parseAge parses an age such as 90d or 2w, or any Go duration like 36h
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			if n, err := strconv.Atoi(number); err == nil && n >= 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not a duration like 90d, 2w or 36h", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,164 @@
+// cmd/key_transfer.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var keyTransferCmd = &cobra.Command{
+	Use:   "transfer [KEY...]",
+	Short: "Move API keys to another team or user",
+	Long: `Reassign API keys to another team and/or user. Keys can be given as secrets
+(sk-...), hashed tokens or aliases, as arguments or one per line on stdin
+("-"), or selected with --from-user to move everything a user owns.
+
+When moving to a team, every model on every key must be allowed by that team.
+Conflicts are listed and nothing is changed until they are resolved, e.g. with
+key update --remove-model. Keys without a model list use the team's models
+and never conflict.
+
+Example:
+  # Move a key to another team
+  navigatorctl key transfer ci-bot --to-team RESEARCH
+
+  # Hand over all keys of a leaving user to a colleague in another team
+  navigatorctl key transfer --from-user jdoe --to-user asmith --to-team CHAT --dry-run`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		fromUser, _ := cmd.Flags().GetString("from-user")
+		toTeam, _ := cmd.Flags().GetString("to-team")
+		toUser, _ := cmd.Flags().GetString("to-user")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if toTeam == "" && toUser == "" {
+			fmt.Fprintln(os.Stderr, "Error: at least one of --to-team or --to-user is required")
+			os.Exit(1)
+		}
+		if fromUser != "" && len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: give either keys or --from-user, not both")
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+
+		var keys []api.KeyObject
+		if fromUser != "" {
+			userID := resolveUserID(cmd, client, fromUser)
+			var err error
+			keys, err = client.ListAllKeysContext(ctx, api.KeyListOptions{UserID: userID})
+			if err != nil {
+				handleError("Error listing user keys", err)
+			}
+			if len(keys) == 0 {
+				fmt.Printf("User %s has no keys\n", fromUser)
+				return
+			}
+		} else {
+			keys = resolveKeyArgs(ctx, client, args)
+		}
+
+		request := api.UpdateKeyRequest{}
+		destination := []string{}
+		if toTeam != "" {
+			team, err := client.GetTeamInfoContext(ctx, toTeam)
+			if err != nil {
+				handleError("Error getting destination team", err)
+			}
+			request.TeamID = &team.TeamID
+			destination = append(destination, fmt.Sprintf("team %s (%s)", team.TeamAlias, team.TeamID))
+
+			if conflicts := modelConflicts(keys, team.Models); len(conflicts) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: team %s does not allow every model on the keys:\n", team.TeamAlias)
+				for _, conflict := range conflicts {
+					fmt.Fprintln(os.Stderr, "  "+conflict)
+				}
+				os.Exit(1)
+			}
+		}
+		if toUser != "" {
+			userID := resolveUserID(cmd, client, toUser)
+			request.UserID = &userID
+			destination = append(destination, "user "+userID)
+		}
+
+		target := strings.Join(destination, " and ")
+		if dryRun {
+			fmt.Printf("The following %d keys would be moved to %s:\n", len(keys), target)
+			printKeyTable(keys)
+			return
+		}
+
+		fmt.Printf("The following %d keys will be moved to %s:\n", len(keys), target)
+		printKeyTable(keys)
+		requireConfirmation(yes, fmt.Sprintf("Move %d keys?", len(keys)))
+
+		failed := 0
+		for _, key := range keys {
+			request.Key = key.Token
+			if _, err := client.UpdateKeyContext(ctx, request); err != nil {
+				if ctx.Err() != nil {
+					handleError("Error moving keys", err)
+				}
+				fmt.Fprintf(os.Stderr, "Error moving key %s: %v\n", maskKey(key.KeyName), err)
+				failed++
+				continue
+			}
+			fmt.Printf("Successfully moved key %s (%s)\n", maskKey(key.KeyName), getOrDefault(key.KeyAlias, "-"))
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Failed to move %d of %d keys\n", failed, len(keys))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	keyTransferCmd.Flags().String("from-user", "", "Move every key owned by this user ID or email")
+	keyTransferCmd.Flags().String("to-team", "", "Team ID or alias to move the keys to")
+	keyTransferCmd.Flags().String("to-user", "", "User ID or email to make the owner of the keys")
+	keyTransferCmd.Flags().Bool("dry-run", false, "Show the keys that would be moved without moving them")
+	keyTransferCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	keyCmd.AddCommand(keyTransferCmd)
+}
+
+// modelConflicts lists the models on keys that the allowed list does not
+// cover, one line per key
+func modelConflicts(keys []api.KeyObject, allowed []string) []string {
+	var conflicts []string
+	for _, key := range keys {
+		var disallowed []string
+		for _, model := range key.Models {
+			if !api.ModelAllowed(model, allowed) {
+				disallowed = append(disallowed, model)
+			}
+		}
+		if len(disallowed) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("key %s (%s): %s",
+				maskKey(key.KeyName), getOrDefault(key.KeyAlias, "-"), strings.Join(disallowed, ", ")))
+		}
+	}
+	return conflicts
+}
+
+// resolveUserID returns the user ID for a user ID or email, exiting if an
+// email belongs to no user
+func resolveUserID(cmd *cobra.Command, client *api.Client, identifier string) string {
+	if !strings.Contains(identifier, "@") {
+		return identifier
+	}
+
+	user, err := client.FindUserByEmailContext(cmd.Context(), identifier)
+	if err != nil {
+		handleError("Error looking up user", err)
+	}
+	return user.UserID
+}
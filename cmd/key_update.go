@@ -0,0 +1,227 @@
+// cmd/key_update.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+// unsetFields maps the names accepted by --unset to the /key/update fields
+// they clear. Clearing the duration removes the key's expiry.
+var unsetFields = map[string]string{
+	"alias":           "key_alias",
+	"team":            "team_id",
+	"max-budget":      "max_budget",
+	"budget-duration": "budget_duration",
+	"tpm-limit":       "tpm_limit",
+	"rpm-limit":       "rpm_limit",
+	"expiry":          "duration",
+}
+
+// keyUpdateResult is the JSON output of key update
+type keyUpdateResult struct {
+	Token   string        `json:"token"`
+	Changes []diff.Change `json:"changes"`
+	Key     api.KeyInfo   `json:"key"`
+}
+
+var keyUpdateCmd = &cobra.Command{
+	Use:   "update KEY",
+	Short: "Update an API key",
+	Long: `Update the budget, limits, expiry, alias, team, models or metadata of an
+API key and show what changed. The key can be given as a secret (sk-...), a
+hashed token or an alias. Only the settings given as flags are changed.
+
+Models can be replaced with --models or edited with --add-model and
+--remove-model. Metadata given with --metadata is merged into the existing
+metadata unless --replace-metadata is set. Use --unset to clear a setting:
+alias, team, max-budget, budget-duration, tpm-limit, rpm-limit or expiry.
+
+Example:
+  # Raise the budget and add a model
+  navigatorctl key update ci-bot --max-budget 50 --add-model gpt-4.1
+
+  # Remove the rate limits and make the key never expire
+  navigatorctl key update ci-bot --unset tpm-limit,rpm-limit,expiry
+
+  # Tag the key and drop an old metadata entry
+  navigatorctl key update ci-bot --metadata owner=platform --remove-metadata ticket`,
+	Annotations: mutating,
+	Args:        cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		client := getAPIClient()
+		key, err := client.ResolveKeyContext(cmd.Context(), args[0])
+		if err != nil {
+			handleError("Error resolving key", err)
+		}
+
+		request, err := buildKeyUpdate(cmd, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if _, err := client.UpdateKeyContext(cmd.Context(), request); err != nil {
+			handleError("Error updating key", err)
+		}
+
+		updated, err := client.GetKeyInfoContext(cmd.Context(), key.Token)
+		if err != nil {
+			handleError("Key updated, but fetching it failed", err)
+		}
+
+		changes, err := keyChanges(key.KeyInfo, updated.Info)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing key: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch output {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(keyUpdateResult{Token: key.Token, Changes: changes, Key: updated.Info}); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Printf("Updated key %s (%s)\n", maskKey(key.KeyName), getOrDefault(updated.Info.KeyAlias, "-"))
+			if len(changes) == 0 {
+				fmt.Println("No changes")
+				return
+			}
+			for _, change := range changes {
+				fmt.Println("  " + change.String())
+			}
+		}
+	},
+}
+
+func init() {
+	keyUpdateCmd.Flags().String("alias", "", "New alias for the key")
+	keyUpdateCmd.Flags().String("team", "", "Team ID or alias to move the key to")
+	keyUpdateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	keyUpdateCmd.Flags().String("budget-duration", "", "Budget reset period, e.g. 30d, 1h")
+	keyUpdateCmd.Flags().Int64("tpm-limit", 0, "Tokens per minute limit")
+	keyUpdateCmd.Flags().Int64("rpm-limit", 0, "Requests per minute limit")
+	keyUpdateCmd.Flags().String("duration", "", "Time from now until the key expires, e.g. 90d")
+	keyUpdateCmd.Flags().StringSlice("models", nil, "Replace the models the key may use")
+	keyUpdateCmd.Flags().StringSlice("add-model", nil, "Allow the key to use these models")
+	keyUpdateCmd.Flags().StringSlice("remove-model", nil, "Stop the key from using these models")
+	keyUpdateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs, merged into the existing metadata")
+	keyUpdateCmd.Flags().Bool("replace-metadata", false, "Replace the existing metadata with --metadata instead of merging")
+	keyUpdateCmd.Flags().StringSlice("remove-metadata", nil, "Metadata keys to remove")
+	keyUpdateCmd.Flags().StringSlice("unset", nil, "Settings to clear: alias, team, max-budget, budget-duration, tpm-limit, rpm-limit, expiry")
+	keyUpdateCmd.Flags().String("output", "table", "Output format: table or json")
+	keyCmd.AddCommand(keyUpdateCmd)
+}
+
+// buildKeyUpdate turns the command's flags into an update request for key,
+// applying incremental model and metadata edits to its current values
+func buildKeyUpdate(cmd *cobra.Command, key *api.KeyObject) (api.UpdateKeyRequest, error) {
+	flags := cmd.Flags()
+	request := api.UpdateKeyRequest{Key: key.Token}
+
+	unset, _ := flags.GetStringSlice("unset")
+	for _, name := range unset {
+		field, ok := unsetFields[name]
+		if !ok {
+			return request, fmt.Errorf("cannot unset %q", name)
+		}
+		flag := name
+		if name == "expiry" {
+			flag = "duration"
+		}
+		if flags.Changed(flag) {
+			return request, fmt.Errorf("--%s cannot be combined with --unset %s", flag, name)
+		}
+		request.Unset = append(request.Unset, field)
+	}
+
+	if flags.Changed("alias") {
+		alias, _ := flags.GetString("alias")
+		request.KeyAlias = &alias
+	}
+	if flags.Changed("team") {
+		team, _ := flags.GetString("team")
+		request.TeamID = &team
+	}
+	if flags.Changed("max-budget") {
+		maxBudget, _ := flags.GetFloat64("max-budget")
+		request.MaxBudget = &maxBudget
+	}
+	if flags.Changed("budget-duration") {
+		budgetDuration, _ := flags.GetString("budget-duration")
+		request.BudgetDuration = &budgetDuration
+	}
+	if flags.Changed("tpm-limit") {
+		tpmLimit, _ := flags.GetInt64("tpm-limit")
+		request.TPMLimit = &tpmLimit
+	}
+	if flags.Changed("rpm-limit") {
+		rpmLimit, _ := flags.GetInt64("rpm-limit")
+		request.RPMLimit = &rpmLimit
+	}
+	if flags.Changed("duration") {
+		duration, _ := flags.GetString("duration")
+		request.Duration = &duration
+	}
+
+	request.Models = mergeModels(cmd, key.Models)
+	metadata, err := mergeMetadata(cmd, key.Metadata)
+	if err != nil {
+		return request, err
+	}
+	request.Metadata = metadata
+
+	return request, requireUpdate(request)
+}
+
+// keyChanges compares two versions of a key, ignoring bookkeeping fields that
+// change on every update
+func keyChanges(before, after api.KeyInfo) ([]diff.Change, error) {
+	changes, err := diff.Fields(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	result := changes[:0]
+	for _, change := range changes {
+		if change.Field == "updated_at" {
+			continue
+		}
+		if change.Field == "models" {
+			// Model order is not significant
+			if sameItems(change.Old, change.New) {
+				continue
+			}
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// sameItems reports whether two decoded JSON lists hold the same strings in
+// any order
+func sameItems(a, b interface{}) bool {
+	toStrings := func(v interface{}) []string {
+		items, _ := v.([]interface{})
+		var s []string
+		for _, item := range items {
+			s = append(s, fmt.Sprint(item))
+		}
+		sort.Strings(s)
+		return s
+	}
+	return strings.Join(toStrings(a), "\x00") == strings.Join(toStrings(b), "\x00")
+}
@@ -5,89 +5,29 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
 
+	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/spf13/cobra"
 )
 
-type ModelInfoParams struct {
-	InputCostPerToken  float64 `json:"input_cost_per_token"`
-	OutputCostPerToken float64 `json:"output_cost_per_token"`
-	ApiBase            string  `json:"api_base"`
-	ApiVersion         string  `json:"api_version"`
-	CustomProvider     string  `json:"custom_llm_provider"`
-}
-
-type ModelInfoDetails struct {
-	ID                string `json:"id"`
-	BaseModel         string `json:"base_model"`
-	Tier              string `json:"tier"`
-	Mode              string `json:"mode"`
-	MaxTokens         int    `json:"max_tokens"`
-	LitellmProvider   string `json:"litellm_provider"`
-	SupportsVision    bool   `json:"supports_vision"`
-	SupportsFunction  bool   `json:"supports_function_calling"`
-	SupportsTool      bool   `json:"supports_tool_choice"`
-	SupportsStreaming bool   `json:"supports_native_streaming"`
-}
-
-type ModelInfoItem struct {
-	ModelName     string           `json:"model_name"`
-	LitellmParams ModelInfoParams  `json:"litellm_params"`
-	ModelInfo     ModelInfoDetails `json:"model_info"`
-}
-
-type ModelInfoResponse struct {
-	Data []ModelInfoItem `json:"data"`
-}
-
 var modelInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Show detailed info for all models",
 	Run: func(cmd *cobra.Command, args []string) {
-		apiURL, _ := cmd.Flags().GetString("api-url")
-		apiKey, _ := cmd.Flags().GetString("api-key")
 		output, _ := cmd.Flags().GetString("output")
 		modelFilter, _ := cmd.Flags().GetString("model")
-		if apiURL == "" || apiKey == "" {
-			fmt.Fprintln(os.Stderr, "API URL and API Key are required")
-			os.Exit(1)
-		}
-
-		url := fmt.Sprintf("%s/model/info", apiURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to create request:", err)
-			os.Exit(1)
-		}
-		req.Header.Set("accept", "application/json")
-		req.Header.Set("x-litellm-api-key", apiKey)
 
-		resp, err := http.DefaultClient.Do(req)
+		client := getAPIClient()
+		result, err := client.GetModelInfoContext(cmd.Context())
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Request failed:", err)
-			os.Exit(1)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := ioutil.ReadAll(resp.Body)
-			fmt.Fprintf(os.Stderr, "API error: %s\n%s\n", resp.Status, string(body))
-			os.Exit(1)
-		}
-
-		var result ModelInfoResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to decode response:", err)
-			os.Exit(1)
+			handleError("Error getting model info", err)
 		}
 
 		// Filter by model if --model is provided
 		filtered := result.Data
 		if modelFilter != "" {
-			filtered = []ModelInfoItem{}
+			filtered = []api.ModelInfo{}
 			for _, m := range result.Data {
 				if m.ModelName == modelFilter || m.ModelInfo.ID == modelFilter {
 					filtered = append(filtered, m)
@@ -5,64 +5,22 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
-type ModelListItem struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	OwnedBy string `json:"owned_by"`
-}
-
-type ModelListResponse struct {
-	Data   []ModelListItem `json:"data"`
-	Object string          `json:"object"`
-}
-
 var modelListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available models",
 	Run: func(cmd *cobra.Command, args []string) {
-		apiURL, _ := cmd.Flags().GetString("api-url")
-		apiKey, _ := cmd.Flags().GetString("api-key")
 		output, _ := cmd.Flags().GetString("output")
-		if apiURL == "" || apiKey == "" {
-			fmt.Fprintln(os.Stderr, "API URL and API Key are required")
-			os.Exit(1)
-		}
-
-		url := fmt.Sprintf("%s/models?return_wildcard_routes=false&include_model_access_groups=false", apiURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to create request:", err)
-			os.Exit(1)
-		}
-		req.Header.Set("accept", "application/json")
-		req.Header.Set("x-litellm-api-key", apiKey)
 
-		resp, err := http.DefaultClient.Do(req)
+		client := getAPIClient()
+		result, err := client.ListModelsContext(cmd.Context())
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Request failed:", err)
-			os.Exit(1)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := ioutil.ReadAll(resp.Body)
-			fmt.Fprintf(os.Stderr, "API error: %s\n%s\n", resp.Status, string(body))
-			os.Exit(1)
-		}
-
-		var result ModelListResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to decode response:", err)
-			os.Exit(1)
+			handleError("Error listing models", err)
 		}
 
 		switch output {
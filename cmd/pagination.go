@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// addPaginationFlags registers the --page, --page-size and --all flags shared
+// by the listing commands
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 1, "Page to fetch")
+	cmd.Flags().Int("page-size", 100, "Number of results per page")
+	cmd.Flags().Bool("all", false, "Fetch every page")
+}
+
+// getPagination returns the values of the pagination flags
+func getPagination(cmd *cobra.Command) (page, pageSize int, all bool) {
+	page, _ = cmd.Flags().GetInt("page")
+	pageSize, _ = cmd.Flags().GetInt("page-size")
+	all, _ = cmd.Flags().GetBool("all")
+
+	if page < 1 || pageSize < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --page and --page-size must be at least 1")
+		os.Exit(1)
+	}
+	return page, pageSize, all
+}
+
+// printPageHint tells the user on stderr when a listing has more pages than
+// were shown, so truncated output is never silent
+func printPageHint(page, totalPages, totalCount int) {
+	if totalPages > page {
+		fmt.Fprintf(os.Stderr, "Showing page %d of %d (%d total). Use --page or --all to see more.\n",
+			page, totalPages, totalCount)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show the changes needed to match YAML manifests",
+	Long: `Read YAML manifests describing users, teams, team memberships and keys,
+compare them with the proxy and print the changes apply would make. Nothing is
+changed.
+
+Each manifest names an object and its desired settings; settings a manifest
+leaves out are not managed. A file may hold several documents separated by
+---, and directories are read recursively.
+
+  apiVersion: navigatorctl/v1
+  kind: User
+  metadata:
+    name: jdoe@example.com       # email or user ID
+  spec:
+    role: internal_user
+    max_budget: 50
+  ---
+  apiVersion: navigatorctl/v1
+  kind: Team
+  metadata:
+    name: CHAT                   # team alias
+  spec:
+    models: [gpt-4.1, gpt-4.1-mini]
+    max_budget: 500
+    budget_duration: 30d
+  ---
+  apiVersion: navigatorctl/v1
+  kind: TeamMembership
+  metadata:
+    name: CHAT                   # every member of the team
+  spec:
+    members:
+      - email: jdoe@example.com
+        role: admin
+  ---
+  apiVersion: navigatorctl/v1
+  kind: Key
+  metadata:
+    name: chat-bot               # key alias; secrets are never stored
+  spec:
+    team: CHAT
+    user: jdoe@example.com
+    max_budget: 25
+    duration: 90d                # only used when the key is created
+
+Objects created or updated by apply are marked with the metadata
+managed_by: navigatorctl. With --prune, marked objects that no manifest
+describes any more, and members missing from a TeamMembership, are deleted;
+without it those deletions are only counted.
+
+Example:
+  navigatorctl plan -f manifests/
+
+  # Include deletions, as JSON
+  navigatorctl plan -f manifests/ --prune --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+
+		plan := loadPlan(cmd)
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(plan); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			printPlan(plan)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	addManifestFlags(planCmd)
+}
+
+// addManifestFlags adds the flags shared by plan and apply
+func addManifestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("file", "f", nil, "Manifest file or directory (repeatable)")
+	cmd.Flags().Bool("prune", false, "Delete managed objects and members that no manifest describes")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	cmd.MarkFlagRequired("file")
+}
+
+// loadPlan reads the manifests given with --file and plans them against the
+// live state
+func loadPlan(cmd *cobra.Command) *manifest.Plan {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	set, err := manifest.Load(files...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading manifests: %v\n", err)
+		os.Exit(1)
+	}
+
+	client := getAPIClient()
+	state, err := manifest.Fetch(cmd.Context(), client, set)
+	if err != nil {
+		handleError("Error reading live state", err)
+	}
+
+	plan, err := manifest.NewPlan(set, state, manifest.PlanOptions{Prune: prune})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return plan
+}
+
+// printPlan lists the planned actions and their field changes
+func printPlan(plan *manifest.Plan) {
+	if len(plan.Actions) == 0 {
+		fmt.Println("No changes; the proxy matches the manifests")
+	}
+	for _, action := range plan.Actions {
+		fmt.Println(action)
+		for _, change := range action.Changes {
+			fmt.Println("    " + change.String())
+		}
+	}
+
+	if len(plan.Actions) > 0 {
+		fmt.Printf("\nPlan: %d to create, %d to update, %d to delete\n",
+			plan.Count(manifest.OpCreate), plan.Count(manifest.OpUpdate), plan.Count(manifest.OpDelete))
+	}
+	if len(plan.Skipped) > 0 {
+		fmt.Printf("%d deletions skipped; run with --prune to include them\n", len(plan.Skipped))
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinReader is shared by every prompt so buffered input is not lost between
+// questions
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// prompt prints question on stderr and returns the trimmed line typed by the
+// user
+func prompt(question string) string {
+	fmt.Fprint(os.Stderr, question)
+	answer, _ := stdinReader.ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// confirm asks a yes/no question; anything other than y or yes means no
+func confirm(question string) bool {
+	answer := strings.ToLower(prompt(question + " [y/N]: "))
+	return answer == "y" || answer == "yes"
+}
+
+// requireConfirmation exits unless --yes was given or the user confirms
+// interactively. Without a terminal on stdin there is nobody to ask, so
+// --yes is required.
+func requireConfirmation(yes bool, question string) {
+	if yes {
+		return
+	}
+	if !isTerminal(os.Stdin) {
+		fmt.Fprintln(os.Stderr, "Error: refusing to continue without confirmation; use --yes when stdin is not a terminal")
+		os.Exit(1)
+	}
+	if !confirm(question) {
+		fmt.Fprintln(os.Stderr, "Aborted")
+		os.Exit(1)
+	}
+}
+
+// writePrivateFile writes data to a new file readable only by the current
+// user. Existing files are never overwritten, so a file that others can read
+// is not reused for secrets.
+func writePrivateFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readLines reads one value per line from r, skipping blank lines and
+// # comments
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
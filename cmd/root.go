@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,12 +24,46 @@ var (
 		Short: "A CLI tool for managing Navigator resources",
 		Long: `navigatorctl is a command line interface for managing Navigator resources.
 It provides functionality for managing teams, members, and API keys.`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Say which proxy is about to be changed before anything happens
+			if cmd.Annotations[annotationMutating] != "" && activeContext != "" && contextErr == nil {
+				fmt.Fprintf(os.Stderr, "Context: %s (%s)\n", activeContext, viper.GetString("api.url"))
+			}
+		},
 	}
+
+	// activeContext is the context selected with --context or current-context;
+	// contextErr is set when the config file does not define it
+	activeContext string
+	contextErr    error
+
+	// configErr is set when the config file exists but cannot be read
+	configErr error
 )
 
+// annotationMutating marks commands that change the proxy, which show the
+// active context before they run
+const annotationMutating = "navigatorctl/mutating"
+
+// mutating is the annotation set of commands that change the proxy
+var mutating = map[string]string{annotationMutating: "true"}
+
+// exitCodeInterrupted is the exit status used when a command is cancelled with
+// Ctrl-C, matching the shell convention of 128+SIGINT.
+const exitCodeInterrupted = 130
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel in-flight requests on the first Ctrl-C; stop listening once the
+	// context is done so a second Ctrl-C terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -31,14 +73,33 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.navigatorctl.yaml)")
+	rootCmd.PersistentFlags().String("context", "", "Context from the config file to use (default is current-context)")
 
 	// API configuration flags
 	rootCmd.PersistentFlags().String("api-url", "", "API URL")
 	rootCmd.PersistentFlags().String("api-key", "", "API Key")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Timeout for each API request (default from config or 30s)")
+
+	// Request behaviour flags
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log every API request and retry to stderr")
+	rootCmd.PersistentFlags().Int("retries", 0, "Number of retries for transient API failures (default from config or 2)")
+	rootCmd.PersistentFlags().Bool("retry-mutations", false, "Also retry POST requests that change state")
 
 	// Bind flags to viper
+	viper.BindPFlag("context", rootCmd.PersistentFlags().Lookup("context"))
 	viper.BindPFlag("api.url", rootCmd.PersistentFlags().Lookup("api-url"))
 	viper.BindPFlag("api.key", rootCmd.PersistentFlags().Lookup("api-key"))
+	viper.BindPFlag("api.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("retry.max_retries", rootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindPFlag("retry.mutations", rootCmd.PersistentFlags().Lookup("retry-mutations"))
+
+	// Request timeout and retry defaults, overridable in the config file
+	viper.SetDefault("api.timeout", 30*time.Second)
+	defaults := api.DefaultRetryPolicy()
+	viper.SetDefault("retry.max_retries", defaults.MaxRetries)
+	viper.SetDefault("retry.initial_backoff", defaults.InitialBackoff)
+	viper.SetDefault("retry.max_backoff", defaults.MaxBackoff)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -63,31 +124,81 @@ func initConfig() {
 		viper.AddConfigPath(".")
 	}
 
-	// Read in environment variables that match
+	// Read in environment variables that match, e.g. NAVIGATOR_API_URL for api.url
 	viper.SetEnvPrefix("NAVIGATOR")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
-	// If a config file is found, read it in.
+	// If a config file is found, read it in. Other commands carry on without
+	// an unreadable one; config validate reports it.
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintf(os.Stderr, "Using config file: %s\n", viper.ConfigFileUsed())
+	} else if !errors.As(err, &viper.ConfigFileNotFoundError{}) && !errors.Is(err, fs.ErrNotExist) {
+		configErr = err
+	}
+
+	// Settings of the selected context override the top-level ones. An
+	// unknown context only fails commands that talk to the proxy, so the config
+	// commands can still repair it.
+	activeContext = viper.GetString("context")
+	if activeContext == "" {
+		activeContext = viper.GetString(config.CurrentContextKey)
+	}
+	if activeContext != "" {
+		contextErr = config.UseContext(viper.GetViper(), activeContext)
+	}
+}
+
+// handleError prints err prefixed with msg and exits. Commands interrupted
+// with Ctrl-C exit with exitCodeInterrupted instead of reporting a failure.
+func handleError(msg string, err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		os.Exit(exitCodeInterrupted)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
+	os.Exit(1)
+}
+
+// getAPIClient creates a new API client using the current configuration
+func getAPIClient() *api.Client {
+	if contextErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", contextErr)
+		os.Exit(1)
 	}
 
 	// Validate required configuration
 	if viper.GetString("api.url") == "" {
-		fmt.Fprintln(os.Stderr, "Error: API URL is required. Set it in config file or use --api-url flag")
+		fmt.Fprintln(os.Stderr, "Error: API URL is required. Run navigatorctl config init, set it in config file or use --api-url flag")
 		os.Exit(1)
 	}
 
 	if viper.GetString("api.key") == "" {
-		fmt.Fprintln(os.Stderr, "Error: API key is required. Set it in config file or use --api-key flag")
+		fmt.Fprintln(os.Stderr, "Error: API key is required. Run navigatorctl config init, set it in config file or use --api-key flag")
 		os.Exit(1)
 	}
+
+	return newAPIClient()
 }
 
-// getAPIClient creates a new API client using the current configuration
-func getAPIClient() *api.Client {
-	return api.NewClient(
+// newAPIClient creates an API client from the current configuration without
+// validating it
+func newAPIClient() *api.Client {
+	client := api.NewClient(
 		viper.GetString("api.url"),
 		viper.GetString("api.key"),
 	)
+
+	client.HTTPClient.Timeout = viper.GetDuration("api.timeout")
+	client.Retry = api.RetryPolicy{
+		MaxRetries:     viper.GetInt("retry.max_retries"),
+		InitialBackoff: viper.GetDuration("retry.initial_backoff"),
+		MaxBackoff:     viper.GetDuration("retry.max_backoff"),
+		RetryMutations: viper.GetBool("retry.mutations"),
+	}
+	if viper.GetBool("verbose") {
+		client.Verbose = os.Stderr
+	}
+
+	return client
 }
@@ -14,7 +14,9 @@ var teamCmd = &cobra.Command{
 	Short: "Manage teams and their members",
 	Long: `Team management commands allow you to:
 - List all teams and their information
-- List, add, and remove team members
+- Create, update, clone and delete teams
+- List, add, update and remove team members
+- Sync team members from a roster file
 - List team API keys
 - View team information and budgets
 
@@ -30,6 +32,10 @@ Examples:
   # Add a team member (using ID or alias)
   navigatorctl team add-member --team-id CLINE --user-id user_456 --role admin
 
+  # Create a team and raise its budget later
+  navigatorctl team create --team-alias CHAT --models gpt-4.1 --max-budget 500
+  navigatorctl team update --team-alias CHAT --max-budget 1000
+
   # View team information (using ID or alias)
   navigatorctl team info --team-id CLINE
   navigatorctl team info --team-id 0dbaa4dd-8523-4e05-8d43-91b7dd80f671`,
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// clonedKey is a key generated for a cloned team. Secret is only shown once.
+type clonedKey struct {
+	SourceAlias string   `json:"source_alias"`
+	KeyAlias    string   `json:"key_alias"`
+	Models      []string `json:"models"`
+	Token       string   `json:"token,omitempty"`
+	Secret      string   `json:"secret,omitempty"`
+}
+
+// cloneResult is the JSON output of team clone
+type cloneResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Source   string           `json:"source_team_id"`
+	Team     *api.Team        `json:"team"`
+	Members  []api.TeamMember `json:"members"`
+	Keys     []clonedKey      `json:"keys"`
+	Failures []string         `json:"failures"`
+}
+
+var teamCloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Create a team from an existing team's settings",
+	Long: `Create a new team copying the models, budget, rate limits and metadata of an
+existing team. --with-members also adds the source team's members with the
+same roles, and --with-keys generates fresh keys with the source keys' models,
+budgets and limits.
+
+Key aliases must be unique, so cloned keys have the source team's alias in
+their alias replaced by the new alias (CHAT-ci-bot becomes CHAT-2-ci-bot), or
+the new alias appended when it does not appear (ci-bot becomes ci-bot-CHAT-2).
+Keys without an alias are numbered (CHAT-2-key-1).
+
+The plan is printed first and you are asked to confirm; --dry-run only prints
+it. The new keys' secrets are shown once.
+
+Example:
+  # Copy settings only
+  navigatorctl team clone --from CHAT --alias CHAT-2
+
+  # Show the full plan including members and keys
+  navigatorctl team clone --from CHAT --alias CHAT-2 --with-members --with-keys --dry-run`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		alias, _ := cmd.Flags().GetString("alias")
+		withMembers, _ := cmd.Flags().GetBool("with-members")
+		withKeys, _ := cmd.Flags().GetBool("with-keys")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		format := getOutputFormat(cmd)
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+		source, err := client.GetTeamInfoContext(ctx, from)
+		if err != nil {
+			handleError("Error getting source team", err)
+		}
+
+		request := api.CreateTeamRequest{
+			TeamAlias:      alias,
+			Models:         source.Models,
+			MaxBudget:      source.MaxBudget,
+			BudgetDuration: source.BudgetDuration,
+			TPMLimit:       source.TPMLimit,
+			RPMLimit:       source.RPMLimit,
+			Metadata:       cloneMetadata(source.Metadata),
+		}
+
+		result := cloneResult{
+			DryRun:   dryRun,
+			Source:   source.TeamID,
+			Members:  []api.TeamMember{},
+			Keys:     []clonedKey{},
+			Failures: []string{},
+		}
+		if withMembers {
+			for _, member := range source.Members {
+				result.Members = append(result.Members, member.TeamMember)
+			}
+		}
+		var sourceKeys []api.KeyObject
+		if withKeys {
+			sourceKeys = source.Keys
+			for i, key := range sourceKeys {
+				result.Keys = append(result.Keys, clonedKey{
+					SourceAlias: key.KeyAlias,
+					KeyAlias:    cloneKeyAlias(key.KeyAlias, source.TeamAlias, alias, i+1),
+					Models:      key.Models,
+				})
+			}
+		}
+
+		plan := os.Stdout
+		if format == "json" {
+			plan = os.Stderr
+		}
+		fmt.Fprintf(plan, "Clone team %s (%s) as %s:\n", source.TeamAlias, source.TeamID, alias)
+		fmt.Fprintf(plan, "  + create team %s with models %s, max budget %s, tpm %s, rpm %s\n",
+			alias, getOrDefault(strings.Join(request.Models, ", "), "all-proxy-models"),
+			formatBudget(request.MaxBudget), formatLimit(request.TPMLimit), formatLimit(request.RPMLimit))
+		for _, member := range result.Members {
+			fmt.Fprintf(plan, "  + add member %s as %s\n", getOrDefault(member.UserEmail, member.UserID), member.Role)
+		}
+		for _, key := range result.Keys {
+			fmt.Fprintf(plan, "  + generate key %s with models %s\n", key.KeyAlias, getOrDefault(strings.Join(key.Models, ", "), "all-team-models"))
+		}
+
+		if dryRun {
+			if format == "json" {
+				printCloneResult(result)
+			}
+			return
+		}
+		requireConfirmation(yes, fmt.Sprintf("Create team %s?", alias))
+
+		team, err := client.CreateTeamContext(ctx, request)
+		if err != nil {
+			handleError("Error creating team", err)
+		}
+		result.Team = team
+
+		for _, member := range result.Members {
+			if _, err := client.AddTeamMemberContext(ctx, team.TeamID, member); err != nil {
+				if ctx.Err() != nil {
+					handleError("Error adding team member", err)
+				}
+				result.Failures = append(result.Failures, fmt.Sprintf("adding member %s: %v", getOrDefault(member.UserEmail, member.UserID), err))
+			}
+		}
+
+		for i, key := range sourceKeys {
+			generated, err := client.GenerateKeyContext(ctx, api.GenerateKeyRequest{
+				KeyAlias:       result.Keys[i].KeyAlias,
+				TeamID:         team.TeamID,
+				Models:         key.Models,
+				MaxBudget:      key.MaxBudget,
+				BudgetDuration: key.BudgetDuration,
+				TPMLimit:       key.TPMLimit,
+				RPMLimit:       key.RPMLimit,
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					// Print what was created so the secrets are not lost
+					break
+				}
+				result.Failures = append(result.Failures, fmt.Sprintf("generating key %s: %v", result.Keys[i].KeyAlias, err))
+				continue
+			}
+			result.Keys[i].Token = generated.HashedToken()
+			result.Keys[i].Secret = generated.Key
+		}
+
+		switch format {
+		case "json":
+			printCloneResult(result)
+		case "table":
+			fmt.Printf("Successfully created team %s (%s)\n", team.TeamAlias, team.TeamID)
+			secrets := false
+			for _, key := range result.Keys {
+				if key.Secret != "" {
+					fmt.Printf("  Key %s: %s\n", key.KeyAlias, key.Secret)
+					secrets = true
+				}
+			}
+			if secrets {
+				fmt.Println("These secrets will not be shown again. Store them somewhere safe now.")
+			}
+		}
+
+		if ctx.Err() != nil {
+			handleError("Error cloning team", ctx.Err())
+		}
+		if len(result.Failures) > 0 {
+			fmt.Fprintf(os.Stderr, "%d steps failed:\n", len(result.Failures))
+			for _, failure := range result.Failures {
+				fmt.Fprintln(os.Stderr, "  "+failure)
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	teamCmd.AddCommand(teamCloneCmd)
+
+	teamCloneCmd.Flags().String("from", "", "Team ID or alias to copy")
+	teamCloneCmd.Flags().String("alias", "", "Alias of the new team")
+	teamCloneCmd.Flags().Bool("with-members", false, "Also add the source team's members")
+	teamCloneCmd.Flags().Bool("with-keys", false, "Also generate keys mirroring the source team's keys")
+	teamCloneCmd.Flags().Bool("dry-run", false, "Print the plan without creating anything")
+	teamCloneCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	teamCloneCmd.MarkFlagRequired("from")
+	teamCloneCmd.MarkFlagRequired("alias")
+}
+
+// cloneKeyAlias derives a unique alias for the n-th cloned key from the source
+// key's alias
+func cloneKeyAlias(keyAlias, sourceTeam, newTeam string, n int) string {
+	if keyAlias == "" {
+		return fmt.Sprintf("%s-key-%d", newTeam, n)
+	}
+	if sourceTeam != "" && strings.Contains(keyAlias, sourceTeam) {
+		return strings.Replace(keyAlias, sourceTeam, newTeam, 1)
+	}
+	return keyAlias + "-" + newTeam
+}
+
+// cloneMetadata copies team metadata without the managed_by marker; the clone
+// is not described by any manifest, so apply --prune must not delete it
+func cloneMetadata(metadata map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if k != manifest.ManagedByKey {
+			result[k] = v
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func printCloneResult(result cloneResult) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
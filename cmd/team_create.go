@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var teamCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a team",
+	Long: `Create a new team. The alias is given with --team-alias; --team-id sets an
+explicit team ID instead of a generated one.
+
+Example:
+  # Team limited to two models with a monthly budget
+  navigatorctl team create --team-alias CHAT --models gpt-4.1,gpt-4.1-mini --max-budget 500 --budget-duration 30d
+
+  # Team with rate limits and metadata
+  navigatorctl team create --team-alias RESEARCH --tpm-limit 100000 --rpm-limit 100 --metadata cost_center=4711`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+
+		request := api.CreateTeamRequest{}
+		request.TeamAlias, _ = cmd.Flags().GetString("team-alias")
+		request.TeamID, _ = cmd.Flags().GetString("team-id")
+		if request.TeamAlias == "" {
+			fmt.Fprintln(os.Stderr, "Error: --team-alias is required")
+			os.Exit(1)
+		}
+		request.Models, _ = cmd.Flags().GetStringSlice("models")
+		request.BudgetDuration, _ = cmd.Flags().GetString("budget-duration")
+		request.Blocked, _ = cmd.Flags().GetBool("blocked")
+		if cmd.Flags().Changed("max-budget") {
+			maxBudget, _ := cmd.Flags().GetFloat64("max-budget")
+			request.MaxBudget = &maxBudget
+		}
+		if cmd.Flags().Changed("tpm-limit") {
+			tpmLimit, _ := cmd.Flags().GetInt64("tpm-limit")
+			request.TPMLimit = &tpmLimit
+		}
+		if cmd.Flags().Changed("rpm-limit") {
+			rpmLimit, _ := cmd.Flags().GetInt64("rpm-limit")
+			request.RPMLimit = &rpmLimit
+		}
+		request.Metadata = getMetadataFlag(cmd, "metadata")
+
+		client := getAPIClient()
+		team, err := client.CreateTeamContext(cmd.Context(), request)
+		if err != nil {
+			handleError("Error creating team", err)
+		}
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(team); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			fmt.Printf("Successfully created team %s (%s)\n", team.TeamAlias, team.TeamID)
+			outputTeamSettingsTable(team)
+		}
+	},
+}
+
+func init() {
+	teamCmd.AddCommand(teamCreateCmd)
+
+	teamCreateCmd.Flags().StringSlice("models", nil, "Models the team may use (default: all models)")
+	teamCreateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	teamCreateCmd.Flags().String("budget-duration", "", "Budget reset period, e.g. 30d, 1h")
+	teamCreateCmd.Flags().Int64("tpm-limit", 0, "Tokens per minute limit")
+	teamCreateCmd.Flags().Int64("rpm-limit", 0, "Requests per minute limit")
+	teamCreateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs")
+	teamCreateCmd.Flags().Bool("blocked", false, "Create the team blocked")
+}
+
+// outputTeamSettingsTable prints the budget, limit and model settings of a
+// team
+func outputTeamSettingsTable(team *api.Team) {
+	table := newFieldTable()
+	table.Append([]string{"Models", getOrDefault(strings.Join(team.Models, ", "), "all-proxy-models")})
+	table.Append([]string{"Max Budget", formatBudget(team.MaxBudget)})
+	table.Append([]string{"Budget Duration", getOrDefault(team.BudgetDuration, "-")})
+	table.Append([]string{"TPM Limit", formatLimit(team.TPMLimit)})
+	table.Append([]string{"RPM Limit", formatLimit(team.RPMLimit)})
+	table.Append([]string{"Blocked", fmt.Sprintf("%t", team.Blocked)})
+	table.Render()
+}
+
+// formatBudget renders an optional budget in USD
+func formatBudget(budget *float64) string {
+	if budget == nil {
+		return "unlimited"
+	}
+	return fmt.Sprintf("$%.2f", *budget)
+}
+
+// formatLimit renders an optional rate limit
+func formatLimit(limit *int64) string {
+	if limit == nil {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", *limit)
+}
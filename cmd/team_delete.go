@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var teamDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a team",
+	Long: `Delete a team together with its keys. The team is shown first and you must
+type its alias to confirm; --yes skips the prompt for scripts.
+
+Example:
+  navigatorctl team delete --team-alias OLD-PROJECT
+
+  # Without a prompt
+  navigatorctl team delete --team-id 0dbaa4dd-8523-4e05-8d43-91b7dd80f671 --yes`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		teamID := getTeamIdentifier(cmd)
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client := getAPIClient()
+		team, err := client.GetTeamInfoContext(cmd.Context(), teamID)
+		if err != nil {
+			handleError("Error getting team info", err)
+		}
+
+		fmt.Printf("The following team and its %d keys will be deleted:\n", len(team.Keys))
+		outputTeamSummaryTable(team)
+
+		if !yes {
+			if !isTerminal(os.Stdin) {
+				fmt.Fprintln(os.Stderr, "Error: refusing to continue without confirmation; use --yes when stdin is not a terminal")
+				os.Exit(1)
+			}
+			// Teams without an alias are confirmed by ID
+			expected := getOrDefault(team.TeamAlias, team.TeamID)
+			answer := prompt(fmt.Sprintf("Type the team alias (%s) to confirm: ", expected))
+			if answer != expected {
+				fmt.Fprintln(os.Stderr, "Aborted: the alias did not match")
+				os.Exit(1)
+			}
+		}
+
+		if err := client.DeleteTeamContext(cmd.Context(), team.TeamID); err != nil {
+			handleError("Error deleting team", err)
+		}
+
+		fmt.Printf("Successfully deleted team %s (%s)\n", team.TeamAlias, team.TeamID)
+	},
+}
+
+func init() {
+	teamCmd.AddCommand(teamDeleteCmd)
+
+	teamDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
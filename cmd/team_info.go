@@ -1,24 +1,35 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
+// teamInfoSections lists the sections team info can show with --show
+var teamInfoSections = []string{"budget", "members", "keys"}
+
 var teamInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Display team information",
 	Long: `Display detailed information about a team including:
-- Team ID and alias
+- Team ID, alias, blocked state and metadata
 - Available models
 - Current spend
 - Creation date
+- Budget, budget reset date and rate limits (budget)
+- Members with their roles, spend and budget (members)
+- Keys with their spend, budget and expiry (keys)
+
+Use --show to pick the sections after the summary. The JSON output is the
+complete team object returned by the server.
 	
 Example:
   # Using team ID
@@ -27,6 +38,9 @@ Example:
   # Using team alias
   navigatorctl team info --team-id CLINE
   
+  # Only the budget and members
+  navigatorctl team info --team-alias CLINE --show budget,members
+  
   # Using JSON output
   navigatorctl team info --team-id CLINE --output json`,
 	Run: showTeamInfo,
@@ -34,52 +48,129 @@ Example:
 
 func init() {
 	teamCmd.AddCommand(teamInfoCmd)
+
+	teamInfoCmd.Flags().StringSlice("show", teamInfoSections, "Sections to show: "+strings.Join(teamInfoSections, ", "))
 }
 
 func showTeamInfo(cmd *cobra.Command, args []string) {
 	teamID := getTeamIdentifier(cmd)
 	format := getOutputFormat(cmd)
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
+	show, _ := cmd.Flags().GetStringSlice("show")
+	sections := make(map[string]bool)
+	for _, section := range show {
+		valid := false
+		for _, known := range teamInfoSections {
+			valid = valid || section == known
+		}
+		if !valid {
+			fmt.Fprintf(os.Stderr, "Error: unknown section %q, must be one of %s\n", section, strings.Join(teamInfoSections, ", "))
+			os.Exit(1)
+		}
+		sections[section] = true
+	}
+
+	client := getAPIClient()
 
-	team, err := client.GetTeamInfo(teamID)
+	team, err := client.GetTeamInfoContext(cmd.Context(), teamID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting team info: %v\n", err)
-		os.Exit(1)
+		handleError("Error getting team info", err)
 	}
 
 	switch format {
 	case "json":
 		outputTeamInfoJSON(team)
 	case "table":
-		outputTeamInfoTable(team)
+		outputTeamInfoTable(team, sections)
 	}
 }
 
-func outputTeamInfoJSON(team *api.Team) {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(team); err != nil {
+// outputTeamInfoJSON prints the team object exactly as the server returned it
+func outputTeamInfoJSON(team *api.TeamDetails) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, team.Raw, "", "  "); err != nil {
 		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
 		os.Exit(1)
 	}
+	out.WriteByte('\n')
+	out.WriteTo(os.Stdout)
 }
 
-func outputTeamInfoTable(team *api.Team) {
-	// Create table for general info
-	infoTable := tablewriter.NewWriter(os.Stdout)
-	infoTable.SetHeader([]string{"Field", "Value"})
-	infoTable.SetBorder(false)
-	infoTable.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	infoTable.SetAlignment(tablewriter.ALIGN_LEFT)
-	infoTable.SetAutoWrapText(false)
+func outputTeamInfoTable(team *api.TeamDetails, sections map[string]bool) {
+	outputTeamSummaryTable(team)
 
+	if sections["budget"] {
+		fmt.Println()
+		fmt.Println("Budget and Limits:")
+		budgetTable := newFieldTable()
+		budgetTable.Append([]string{"Spend", fmt.Sprintf("$%.2f", team.Spend)})
+		budgetTable.Append([]string{"Max Budget", formatBudget(team.MaxBudget)})
+		budgetTable.Append([]string{"Budget Duration", getOrDefault(team.BudgetDuration, "-")})
+		budgetTable.Append([]string{"Budget Resets At", getOrDefault(team.BudgetResetAt, "-")})
+		budgetTable.Append([]string{"TPM Limit", formatLimit(team.TPMLimit)})
+		budgetTable.Append([]string{"RPM Limit", formatLimit(team.RPMLimit)})
+		budgetTable.Render()
+	}
+
+	if sections["members"] {
+		fmt.Println()
+		fmt.Printf("Members (%d):\n", len(team.Members))
+		outputTable(team.Members)
+	}
+
+	if sections["keys"] {
+		fmt.Println()
+		fmt.Printf("Keys (%d):\n", len(team.Keys))
+		keysTable := tablewriter.NewWriter(os.Stdout)
+		keysTable.SetHeader([]string{"Key Name", "Alias", "Spend", "Max Budget", "Budget Resets At", "Expires", "Blocked"})
+		keysTable.SetBorder(false)
+		keysTable.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		keysTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, key := range team.Keys {
+			keysTable.Append([]string{
+				maskKey(key.KeyName),
+				getOrDefault(key.KeyAlias, "-"),
+				fmt.Sprintf("$%.2f", key.Spend),
+				formatBudget(key.MaxBudget),
+				getOrDefault(key.BudgetResetAt, "-"),
+				getOrDefault(key.Expires, "never"),
+				fmt.Sprintf("%t", key.Blocked),
+			})
+		}
+		keysTable.Render()
+	}
+}
+
+// outputTeamSummaryTable prints the identifying fields of a team
+func outputTeamSummaryTable(team *api.TeamDetails) {
+	infoTable := newFieldTable()
 	infoTable.Append([]string{"Team ID", team.TeamID})
 	infoTable.Append([]string{"Alias", team.TeamAlias})
 	infoTable.Append([]string{"Spend", fmt.Sprintf("$%.2f", team.Spend)})
 	infoTable.Append([]string{"Created At", team.CreatedAt})
 	infoTable.Append([]string{"Models", fmt.Sprintf("%v", team.Models)})
+	infoTable.Append([]string{"Blocked", fmt.Sprintf("%t", team.Blocked)})
+
+	keys := make([]string, 0, len(team.Metadata))
+	for k := range team.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		infoTable.Append([]string{"Metadata: " + k, fmt.Sprint(team.Metadata[k])})
+	}
 
 	fmt.Println("Team Information:")
 	infoTable.Render()
 }
+
+// newFieldTable returns a borderless two column Field/Value table
+func newFieldTable() *tablewriter.Table {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Field", "Value"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	return table
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -9,7 +11,6 @@ import (
 	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var listKeysCmd = &cobra.Command{
@@ -25,31 +26,62 @@ Example:
   navigatorctl team keys --team-alias CHAT
   
   # Using JSON output
-  navigatorctl team keys --team-alias CHAT --output json`,
+  navigatorctl team keys --team-alias CHAT --output json
+
+  # Fetch every page instead of the first 100 keys
+  navigatorctl team keys --team-alias CHAT --all`,
 	Run: listKeys,
 }
 
 func init() {
+	addPaginationFlags(listKeysCmd)
+	listKeysCmd.Flags().Int("concurrency", api.DefaultConcurrency, "Parallel key detail requests when the server does not return full key objects")
 	teamCmd.AddCommand(listKeysCmd)
 }
 
 func listKeys(cmd *cobra.Command, args []string) {
 	teamID := getTeamIdentifier(cmd)
 	format := getOutputFormat(cmd)
+	page, pageSize, all := getPagination(cmd)
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
+	client := getAPIClient()
 
-	keys, err := client.ListTeamKeys(teamID)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listing team keys: %v\n", err)
-		os.Exit(1)
+	result, err := client.ListTeamKeysContext(cmd.Context(), teamID, api.KeyListOptions{
+		Page:        page,
+		PageSize:    pageSize,
+		AllPages:    all,
+		Concurrency: concurrency,
+	})
+	if err != nil && !(errors.Is(err, context.Canceled) && result != nil) {
+		handleError("Error listing team keys", err)
 	}
 
 	switch format {
 	case "json":
-		outputKeysJSON(keys)
+		outputKeysJSON(result.Keys)
 	case "table":
-		outputKeysTable(keys)
+		outputKeysTable(result.Keys)
+	}
+
+	// Keys whose details could not be fetched are left out of the listing
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+	if len(result.Warnings) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d keys were skipped\n", len(result.Warnings))
+	}
+
+	// Interrupted part way through fetching key details: the partial list has
+	// been printed, report it and exit with the interrupted status.
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Interrupted: showing %d of %d keys fetched before cancellation\n",
+			len(result.Keys), result.TotalCount)
+		os.Exit(exitCodeInterrupted)
+	}
+
+	if !all {
+		printPageHint(page, result.TotalPages, result.TotalCount)
 	}
 }
 
@@ -20,10 +20,9 @@ Example:
   navigatorctl team list --output json`,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getAPIClient()
-		teams, err := client.ListTeams()
+		teams, err := client.ListTeamsContext(cmd.Context())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error listing teams: %v\n", err)
-			os.Exit(1)
+			handleError("Error listing teams", err)
 		}
 
 		outputFormat := getOutputFormat(cmd)
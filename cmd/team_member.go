@@ -1,14 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -32,29 +34,56 @@ Example:
 	addMemberCmd = &cobra.Command{
 		Use:   "add-member",
 		Short: "Add a member to a team",
-		Long: `Add a new member to a team with specified role.
+		Long: `Add a new member to a team with specified role. The member can be given by
+user ID or by email alone; an email is resolved to the existing user, and with
+--create-user a user is created when none has that email.
 	
 Example:
   # Using team ID
   navigatorctl team add-member --team-id 0dbaa4dd-8523-4e05-8d43-91b7dd80f671 --user-id user_456 --role admin
   
   # Using team alias
-  navigatorctl team add-member --team-alias CLINE --user-id user_456 --role member`,
-		Run: addMember,
+  navigatorctl team add-member --team-alias CLINE --user-id user_456 --role user
+  
+  # By email, creating the user if needed
+  navigatorctl team add-member --team-alias CLINE --email jdoe@example.com --role user --create-user`,
+		Annotations: mutating,
+		Run:         addMember,
 	}
 
 	removeMemberCmd = &cobra.Command{
 		Use:   "remove-member",
 		Short: "Remove a member from a team",
-		Long: `Remove a member from a team.
+		Long: `Remove a member from a team. The member can be given by user ID or by
+email alone, which is resolved to the user's ID.
 	
 Example:
   # Using team ID
   navigatorctl team remove-member --team-id 0dbaa4dd-8523-4e05-8d43-91b7dd80f671 --user-id user_456
   
   # Using team alias
-  navigatorctl team remove-member --team-alias CLINE --user-id user_456`,
-		Run: removeMember,
+  navigatorctl team remove-member --team-alias CLINE --user-id user_456
+  
+  # By email
+  navigatorctl team remove-member --team-alias CLINE --email jdoe@example.com`,
+		Annotations: mutating,
+		Run:         removeMember,
+	}
+
+	updateMemberCmd = &cobra.Command{
+		Use:   "update-member",
+		Short: "Change a team member's role or budget",
+		Long: `Change a member's role or their budget within a team without removing and
+re-adding them. The member is identified by --user-id or --email.
+	
+Example:
+  # Promote a member to admin
+  navigatorctl team update-member --team-alias CLINE --user-id user_456 --role admin
+  
+  # Limit a member's spend within the team to $50
+  navigatorctl team update-member --team-alias CLINE --email jdoe@example.com --max-budget 50`,
+		Annotations: mutating,
+		Run:         updateMember,
 	}
 )
 
@@ -62,70 +91,146 @@ func init() {
 	teamCmd.AddCommand(listMembersCmd)
 	teamCmd.AddCommand(addMemberCmd)
 	teamCmd.AddCommand(removeMemberCmd)
+	teamCmd.AddCommand(updateMemberCmd)
 
 	// Add flags for member management
 	addMemberCmd.Flags().StringP("user-id", "u", "", "User ID to add")
 	addMemberCmd.Flags().StringP("email", "e", "", "User email address")
 	addMemberCmd.Flags().StringP("role", "r", "", "Role to assign (admin/user)")
-	addMemberCmd.MarkFlagRequired("user-id")
+	addMemberCmd.Flags().Bool("create-user", false, "Create the user if no user has the given email")
 	addMemberCmd.MarkFlagRequired("role")
 
 	removeMemberCmd.Flags().StringP("user-id", "u", "", "User ID to remove")
 	removeMemberCmd.Flags().StringP("email", "e", "", "User email address")
-	removeMemberCmd.MarkFlagRequired("user-id")
+
+	updateMemberCmd.Flags().StringP("user-id", "u", "", "User ID of the member")
+	updateMemberCmd.Flags().StringP("email", "e", "", "User email address of the member")
+	updateMemberCmd.Flags().StringP("role", "r", "", "New role (admin/user)")
+	updateMemberCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD for this member within the team")
 }
 
 func addMember(cmd *cobra.Command, args []string) {
 	teamID := getTeamIdentifier(cmd)
 	userID, _ := cmd.Flags().GetString("user-id")
+	email, _ := cmd.Flags().GetString("email")
 	role, _ := cmd.Flags().GetString("role")
+	createUser, _ := cmd.Flags().GetBool("create-user")
 
 	if role != "admin" && role != "user" {
 		fmt.Fprintln(os.Stderr, "Error: role must be either 'admin' or 'user'")
 		os.Exit(1)
 	}
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
-
-	email, _ := cmd.Flags().GetString("email")
+	client := getAPIClient()
 
-	member := api.TeamMember{
-		UserID:    userID,
-		UserEmail: email,
-		Role:      role,
-	}
+	member := resolveMember(cmd.Context(), client, userID, email, createUser)
+	member.Role = role
 
-	response, err := client.AddTeamMember(teamID, member)
+	response, err := client.AddTeamMemberContext(cmd.Context(), teamID, member)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error adding team member: %v\n", err)
-		os.Exit(1)
+		handleError("Error adding team member", err)
 	}
 
 	fmt.Printf("Successfully added user %s to team %s (%s) with role %s\n",
-		userID, response.TeamID, response.TeamAlias, role)
+		member.UserID, response.TeamID, response.TeamAlias, role)
 }
 
 func removeMember(cmd *cobra.Command, args []string) {
 	teamID := getTeamIdentifier(cmd)
 	userID, _ := cmd.Flags().GetString("user-id")
+	email, _ := cmd.Flags().GetString("email")
+
+	client := getAPIClient()
+
+	member := resolveMember(cmd.Context(), client, userID, email, false)
+
+	response, err := client.RemoveTeamMemberContext(cmd.Context(), teamID, member)
+	if err != nil {
+		handleError("Error removing team member", err)
+	}
+
+	fmt.Printf("Successfully removed user %s from team %s (%s)\n",
+		member.UserID, response.TeamID, response.TeamAlias)
+}
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
+// resolveMember returns the team member for a user ID or, when only an
+// email is given, the user with that email, creating it if create is set. It
+// prints which path was taken and exits if the user cannot be found.
+func resolveMember(ctx context.Context, client *api.Client, userID, email string, create bool) api.TeamMember {
+	if userID != "" {
+		return api.TeamMember{UserID: userID, UserEmail: email}
+	}
+	if email == "" {
+		fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+		os.Exit(1)
+	}
 
+	user, err := client.FindUserByEmailContext(ctx, email)
+	if err == nil {
+		fmt.Printf("Resolved %s to existing user %s\n", email, user.UserID)
+		return api.TeamMember{UserID: user.UserID, UserEmail: email}
+	}
+	if !errors.Is(err, api.ErrUserNotFound) {
+		handleError("Error looking up user", err)
+	}
+	if !create {
+		fmt.Fprintf(os.Stderr, "Error: no user has the email %s; use --create-user to create one\n", email)
+		os.Exit(1)
+	}
+
+	created, err := client.CreateUserContext(ctx, api.CreateUserRequest{UserEmail: email})
+	if err != nil {
+		handleError("Error creating user", err)
+	}
+	fmt.Printf("Created user %s for %s\n", created.UserID, email)
+	return api.TeamMember{UserID: created.UserID, UserEmail: email}
+}
+
+func updateMember(cmd *cobra.Command, args []string) {
+	teamID := getTeamIdentifier(cmd)
+	userID, _ := cmd.Flags().GetString("user-id")
 	email, _ := cmd.Flags().GetString("email")
+	role, _ := cmd.Flags().GetString("role")
 
-	member := api.TeamMember{
+	if userID == "" && email == "" {
+		fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+		os.Exit(1)
+	}
+	if role != "" && role != "admin" && role != "user" {
+		fmt.Fprintln(os.Stderr, "Error: role must be either 'admin' or 'user'")
+		os.Exit(1)
+	}
+	if role == "" && !cmd.Flags().Changed("max-budget") {
+		fmt.Fprintln(os.Stderr, "Error: nothing to update, give --role and/or --max-budget")
+		os.Exit(1)
+	}
+
+	request := api.UpdateTeamMemberRequest{
+		TeamID:    teamID,
 		UserID:    userID,
 		UserEmail: email,
+		Role:      role,
+	}
+	if cmd.Flags().Changed("max-budget") {
+		maxBudget, _ := cmd.Flags().GetFloat64("max-budget")
+		request.MaxBudgetInTeam = &maxBudget
 	}
 
-	response, err := client.RemoveTeamMember(teamID, member)
+	client := getAPIClient()
+	response, err := client.UpdateTeamMemberContext(cmd.Context(), request)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error removing team member: %v\n", err)
-		os.Exit(1)
+		handleError("Error updating team member", err)
 	}
 
-	fmt.Printf("Successfully removed user %s from team %s (%s)\n",
-		userID, response.TeamID, response.TeamAlias)
+	member := getOrDefault(response.UserID, getOrDefault(userID, email))
+	var changes []string
+	if role != "" {
+		changes = append(changes, "role "+role)
+	}
+	if request.MaxBudgetInTeam != nil {
+		changes = append(changes, "budget "+formatBudget(request.MaxBudgetInTeam))
+	}
+	fmt.Printf("Successfully updated user %s in team %s: %s\n", member, response.TeamID, strings.Join(changes, ", "))
 }
 
 func listMembers(cmd *cobra.Command, args []string) {
@@ -133,12 +238,11 @@ func listMembers(cmd *cobra.Command, args []string) {
 	format := getOutputFormat(cmd)
 
 	// Get API client from root command
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
+	client := getAPIClient()
 
-	members, err := client.ListTeamMembers(teamID)
+	members, err := client.ListTeamMemberDetailsContext(cmd.Context(), teamID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listing team members: %v\n", err)
-		os.Exit(1)
+		handleError("Error listing team members", err)
 	}
 
 	switch format {
@@ -149,7 +253,7 @@ func listMembers(cmd *cobra.Command, args []string) {
 	}
 }
 
-func outputJSON(members []api.TeamMember) {
+func outputJSON(members []api.TeamMemberDetails) {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(members); err != nil {
@@ -158,9 +262,9 @@ func outputJSON(members []api.TeamMember) {
 	}
 }
 
-func outputTable(members []api.TeamMember) {
+func outputTable(members []api.TeamMemberDetails) {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"User ID", "Email", "Role"})
+	table.SetHeader([]string{"User ID", "Email", "Role", "Spend", "Budget"})
 	table.SetBorder(false)
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
@@ -176,6 +280,8 @@ func outputTable(members []api.TeamMember) {
 			member.UserID,
 			email,
 			member.Role,
+			fmt.Sprintf("$%.2f", member.Spend),
+			formatBudget(member.MaxBudgetInTeam),
 		})
 	}
 
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+	"github.com/spf13/cobra"
+)
+
+// syncFailure records a team or membership change that could not be made
+type syncFailure struct {
+	Team   string         `json:"team"`
+	Action *roster.Action `json:"action,omitempty"`
+	Error  string         `json:"error"`
+}
+
+// syncResult is the JSON output of team sync-members
+type syncResult struct {
+	Applied  bool            `json:"applied"`
+	Actions  []roster.Action `json:"actions"`
+	Failures []syncFailure   `json:"failures"`
+}
+
+var teamSyncMembersCmd = &cobra.Command{
+	Use:   "sync-members",
+	Short: "Sync team members from a roster file",
+	Long: `Compare team memberships with a roster file and print the members to add,
+remove and change roles for. Nothing changes unless --apply is given. With
+--no-remove, members missing from the roster are kept.
+
+A CSV roster has a header row with the columns team, user_id, email and role;
+each row is one member and a file can cover many teams. Role defaults to user.
+
+  team,email,user_id,role
+  CHAT,jdoe@example.com,,admin
+  CHAT,,user_123,user
+  RESEARCH,asmith@example.com,,user
+
+A YAML roster lists the members per team:
+
+  teams:
+    - team: CHAT
+      members:
+        - email: jdoe@example.com
+          role: admin
+        - user_id: user_123
+
+Members given only by email are matched to existing users; unknown emails
+are added by email, which creates the user. Failures on individual members
+are reported at the end without stopping the rest of the sync. A member whose
+user lookup fails is skipped, and no members are removed from that team.
+
+Example:
+  # Show the plan
+  navigatorctl team sync-members --file roster.csv
+
+  # Apply it, only adding members
+  navigatorctl team sync-members --file roster.yaml --apply --no-remove`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		apply, _ := cmd.Flags().GetBool("apply")
+		noRemove, _ := cmd.Flags().GetBool("no-remove")
+		format := getOutputFormat(cmd)
+
+		desired, err := roster.Load(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading roster: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+		teams, err := client.ListTeamsContext(ctx)
+		if err != nil {
+			handleError("Error listing teams", err)
+		}
+
+		result := syncResult{Applied: apply, Actions: []roster.Action{}, Failures: []syncFailure{}}
+		teamIDs := make(map[string]string)
+		users := newUserCache(client)
+		for _, team := range desired.Teams {
+			teamID, ok := findTeamID(teams, team.Team)
+			if !ok {
+				result.Failures = append(result.Failures, syncFailure{Team: team.Team, Error: "team not found"})
+				continue
+			}
+			teamIDs[team.Team] = teamID
+
+			current, err := client.ListTeamMembersContext(ctx, teamID)
+			if err != nil {
+				if ctx.Err() != nil {
+					handleError("Error listing team members", err)
+				}
+				result.Failures = append(result.Failures, syncFailure{Team: team.Team, Error: err.Error()})
+				continue
+			}
+
+			// Members whose lookup fails are skipped and reported
+			var members []roster.Member
+			skipped := false
+			for _, member := range team.Members {
+				if member.UserID == "" {
+					userID, err := users.lookup(ctx, member.Email)
+					if err != nil {
+						if ctx.Err() != nil {
+							handleError("Error looking up user", err)
+						}
+						result.Failures = append(result.Failures, syncFailure{Team: team.Team, Error: fmt.Sprintf("skipped %s: %v", member.Email, err)})
+						skipped = true
+						continue
+					}
+					member.UserID = userID
+				}
+				members = append(members, member)
+			}
+
+			// A skipped member may be in the team under a user ID alone, so no
+			// member can safely be removed
+			actions := roster.Plan(team.Team, members, current, roster.PlanOptions{NoRemove: noRemove || skipped})
+			result.Actions = append(result.Actions, actions...)
+		}
+
+		if format == "table" {
+			printSyncPlan(desired, result)
+		}
+
+		if apply {
+			for i := range result.Actions {
+				action := &result.Actions[i]
+				if err := applySyncAction(ctx, client, teamIDs[action.Team], *action); err != nil {
+					if ctx.Err() != nil {
+						handleError("Error syncing team members", err)
+					}
+					result.Failures = append(result.Failures, syncFailure{Team: action.Team, Action: action, Error: err.Error()})
+				}
+			}
+		}
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			switch {
+			case len(result.Actions) == 0:
+			case apply:
+				fmt.Printf("Applied %d of %d changes\n", len(result.Actions)-countActionFailures(result.Failures), len(result.Actions))
+			default:
+				fmt.Println("Run again with --apply to make these changes")
+			}
+		}
+
+		if len(result.Failures) > 0 {
+			fmt.Fprintf(os.Stderr, "%d failures:\n", len(result.Failures))
+			for _, failure := range result.Failures {
+				if failure.Action != nil {
+					fmt.Fprintf(os.Stderr, "  %s: %s: %s\n", failure.Team, failure.Action, failure.Error)
+				} else {
+					fmt.Fprintf(os.Stderr, "  %s: %s\n", failure.Team, failure.Error)
+				}
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	teamCmd.AddCommand(teamSyncMembersCmd)
+
+	teamSyncMembersCmd.Flags().StringP("file", "f", "", "Roster file (.csv, .yaml or .yml)")
+	teamSyncMembersCmd.Flags().Bool("apply", false, "Make the planned changes instead of only printing them")
+	teamSyncMembersCmd.Flags().Bool("no-remove", false, "Only add members and change roles, never remove members")
+	teamSyncMembersCmd.MarkFlagRequired("file")
+}
+
+// findTeamID looks up a team by ID or alias in a team listing
+func findTeamID(teams []api.Team, identifier string) (string, bool) {
+	for _, team := range teams {
+		if team.TeamID == identifier || team.TeamAlias == identifier {
+			return team.TeamID, true
+		}
+	}
+	return "", false
+}
+
+// userCache resolves emails to user IDs once per run
+type userCache struct {
+	client *api.Client
+	ids    map[string]string
+}
+
+func newUserCache(client *api.Client) *userCache {
+	return &userCache{client: client, ids: make(map[string]string)}
+}
+
+// lookup returns the ID of the user with email, or "" if there is none
+func (c *userCache) lookup(ctx context.Context, email string) (string, error) {
+	key := strings.ToLower(email)
+	if id, ok := c.ids[key]; ok {
+		return id, nil
+	}
+
+	user, err := c.client.FindUserByEmailContext(ctx, email)
+	if err != nil && !errors.Is(err, api.ErrUserNotFound) {
+		return "", err
+	}
+	if user != nil {
+		c.ids[key] = user.UserID
+	} else {
+		c.ids[key] = ""
+	}
+	return c.ids[key], nil
+}
+
+// applySyncAction makes a single planned membership change
+func applySyncAction(ctx context.Context, client *api.Client, teamID string, action roster.Action) error {
+	member := api.TeamMember{
+		UserID:    action.Member.UserID,
+		UserEmail: action.Member.Email,
+		Role:      action.Member.Role,
+	}
+
+	var err error
+	switch action.Type {
+	case roster.ActionAdd:
+		_, err = client.AddTeamMemberContext(ctx, teamID, member)
+	case roster.ActionRemove:
+		_, err = client.RemoveTeamMemberContext(ctx, teamID, member)
+	case roster.ActionUpdateRole:
+		_, err = client.UpdateTeamMemberContext(ctx, api.UpdateTeamMemberRequest{
+			TeamID:    teamID,
+			UserID:    member.UserID,
+			UserEmail: member.UserEmail,
+			Role:      member.Role,
+		})
+	default:
+		err = fmt.Errorf("unknown action %q", action.Type)
+	}
+	return err
+}
+
+// printSyncPlan lists the planned actions grouped by team in roster order
+func printSyncPlan(desired *roster.Roster, result syncResult) {
+	if len(result.Actions) == 0 {
+		fmt.Println("All teams match the roster")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, team := range desired.Teams {
+		var lines []string
+		for _, action := range result.Actions {
+			if action.Team == team.Team {
+				lines = append(lines, action.String())
+				counts[action.Type]++
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Printf("Team %s:\n", team.Team)
+		for _, line := range lines {
+			fmt.Println("  " + line)
+		}
+	}
+
+	fmt.Printf("Plan: %d to add, %d to change, %d to remove\n",
+		counts[roster.ActionAdd], counts[roster.ActionUpdateRole], counts[roster.ActionRemove])
+}
+
+// countActionFailures counts the failures caused by applying an action, as
+// opposed to teams that could not be read
+func countActionFailures(failures []syncFailure) int {
+	n := 0
+	for _, failure := range failures {
+		if failure.Action != nil {
+			n++
+		}
+	}
+	return n
+}
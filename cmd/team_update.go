@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+// teamUnsetFields maps the names accepted by team update --unset to the flag
+// that sets them and the /team/update field they clear
+var teamUnsetFields = map[string]struct{ flag, field string }{
+	"max-budget":      {"max-budget", "max_budget"},
+	"budget-duration": {"budget-duration", "budget_duration"},
+	"tpm-limit":       {"tpm-limit", "tpm_limit"},
+	"rpm-limit":       {"rpm-limit", "rpm_limit"},
+}
+
+var teamUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a team",
+	Long: `Update the alias, models, budget, limits, metadata or blocked status of a
+team and show what changed. Only the settings given as flags are changed.
+
+Models can be replaced with --models or edited with --add-model and
+--remove-model. Metadata given with --metadata is merged into the existing
+metadata unless --replace-metadata is set. Use --unset to clear a setting:
+max-budget, budget-duration, tpm-limit or rpm-limit.
+
+Example:
+  # Rename a team
+  navigatorctl team update --team-alias CHAT --new-alias CHAT-PROD
+
+  # Raise the budget and allow one more model
+  navigatorctl team update --team-alias CHAT --max-budget 1000 --add-model gpt-4.1
+
+  # Block a team and remove its rate limits
+  navigatorctl team update --team-id 0dbaa4dd-8523-4e05-8d43-91b7dd80f671 --blocked --unset tpm-limit,rpm-limit`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		teamID := getTeamIdentifier(cmd)
+		format := getOutputFormat(cmd)
+
+		client := getAPIClient()
+		before, err := client.GetTeamInfoContext(cmd.Context(), teamID)
+		if err != nil {
+			handleError("Error getting team info", err)
+		}
+
+		request, err := buildTeamUpdate(cmd, &before.Team)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		after, err := client.UpdateTeamContext(cmd.Context(), request)
+		if err != nil {
+			handleError("Error updating team", err)
+		}
+
+		changes, err := diff.Fields(before.Team, after)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing team: %v\n", err)
+			os.Exit(1)
+		}
+		changes = withoutField(changes, "updated_at")
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			result := struct {
+				Changes []diff.Change `json:"changes"`
+				Team    *api.Team     `json:"team"`
+			}{changes, after}
+			if err := encoder.Encode(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			fmt.Printf("Successfully updated team %s (%s)\n", after.TeamAlias, after.TeamID)
+			if len(changes) == 0 {
+				fmt.Println("No changes")
+				return
+			}
+			for _, change := range changes {
+				fmt.Println("  " + change.String())
+			}
+		}
+	},
+}
+
+func init() {
+	teamCmd.AddCommand(teamUpdateCmd)
+
+	teamUpdateCmd.Flags().String("new-alias", "", "New alias for the team")
+	teamUpdateCmd.Flags().StringSlice("models", nil, "Replace the models the team may use")
+	teamUpdateCmd.Flags().StringSlice("add-model", nil, "Allow the team to use these models")
+	teamUpdateCmd.Flags().StringSlice("remove-model", nil, "Stop the team from using these models")
+	teamUpdateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	teamUpdateCmd.Flags().String("budget-duration", "", "Budget reset period, e.g. 30d, 1h")
+	teamUpdateCmd.Flags().Int64("tpm-limit", 0, "Tokens per minute limit")
+	teamUpdateCmd.Flags().Int64("rpm-limit", 0, "Requests per minute limit")
+	teamUpdateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs, merged into the existing metadata")
+	teamUpdateCmd.Flags().Bool("replace-metadata", false, "Replace the existing metadata with --metadata instead of merging")
+	teamUpdateCmd.Flags().StringSlice("remove-metadata", nil, "Metadata keys to remove")
+	teamUpdateCmd.Flags().Bool("blocked", false, "Block (--blocked) or unblock (--blocked=false) the team")
+	teamUpdateCmd.Flags().StringSlice("unset", nil, "Settings to clear: max-budget, budget-duration, tpm-limit, rpm-limit")
+}
+
+// buildTeamUpdate turns the command's flags into an update request for team,
+// applying incremental model and metadata edits to its current values
+func buildTeamUpdate(cmd *cobra.Command, team *api.Team) (api.UpdateTeamRequest, error) {
+	flags := cmd.Flags()
+	request := api.UpdateTeamRequest{TeamID: team.TeamID}
+
+	unset, _ := flags.GetStringSlice("unset")
+	for _, name := range unset {
+		setting, ok := teamUnsetFields[name]
+		if !ok {
+			return request, fmt.Errorf("cannot unset %q", name)
+		}
+		if flags.Changed(setting.flag) {
+			return request, fmt.Errorf("--%s cannot be combined with --unset %s", setting.flag, name)
+		}
+		request.Unset = append(request.Unset, setting.field)
+	}
+
+	if flags.Changed("new-alias") {
+		alias, _ := flags.GetString("new-alias")
+		request.TeamAlias = &alias
+	}
+	if flags.Changed("max-budget") {
+		maxBudget, _ := flags.GetFloat64("max-budget")
+		request.MaxBudget = &maxBudget
+	}
+	if flags.Changed("budget-duration") {
+		budgetDuration, _ := flags.GetString("budget-duration")
+		request.BudgetDuration = &budgetDuration
+	}
+	if flags.Changed("tpm-limit") {
+		tpmLimit, _ := flags.GetInt64("tpm-limit")
+		request.TPMLimit = &tpmLimit
+	}
+	if flags.Changed("rpm-limit") {
+		rpmLimit, _ := flags.GetInt64("rpm-limit")
+		request.RPMLimit = &rpmLimit
+	}
+	if flags.Changed("blocked") {
+		blocked, _ := flags.GetBool("blocked")
+		request.Blocked = &blocked
+	}
+
+	request.Models = mergeModels(cmd, team.Models)
+	metadata, err := mergeMetadata(cmd, team.Metadata)
+	if err != nil {
+		return request, err
+	}
+	request.Metadata = metadata
+
+	return request, requireUpdate(request)
+}
+
+// withoutField drops the change to field, typically a timestamp that changes
+// on every update
+func withoutField(changes []diff.Change, field string) []diff.Change {
+	result := changes[:0]
+	for _, change := range changes {
+		if change.Field != field {
+			result = append(result, change)
+		}
+	}
+	return result
+}
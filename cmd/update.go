@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeModels applies --models, --add-model and --remove-model to the current
+// model list. It returns nil when none of them was given, leaving the models
+// unchanged.
+func mergeModels(cmd *cobra.Command, current []string) []string {
+	flags := cmd.Flags()
+	if !flags.Changed("models") && !flags.Changed("add-model") && !flags.Changed("remove-model") {
+		return nil
+	}
+
+	models := current
+	if flags.Changed("models") {
+		models, _ = flags.GetStringSlice("models")
+	}
+	add, _ := flags.GetStringSlice("add-model")
+	remove, _ := flags.GetStringSlice("remove-model")
+	return editList(models, add, remove)
+}
+
+// mergeMetadata applies --metadata, --replace-metadata and --remove-metadata
+// to the current metadata. It returns nil when neither --metadata nor
+// --remove-metadata was given, leaving the metadata unchanged.
+func mergeMetadata(cmd *cobra.Command, current map[string]interface{}) (map[string]interface{}, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("metadata") && !flags.Changed("remove-metadata") {
+		if flags.Changed("replace-metadata") {
+			return nil, fmt.Errorf("--replace-metadata requires --metadata")
+		}
+		return nil, nil
+	}
+
+	replace, _ := flags.GetBool("replace-metadata")
+	metadata := make(map[string]interface{})
+	if !replace {
+		for k, v := range current {
+			metadata[k] = v
+		}
+	}
+	for k, v := range getMetadataFlag(cmd, "metadata") {
+		metadata[k] = v
+	}
+	remove, _ := flags.GetStringSlice("remove-metadata")
+	for _, k := range remove {
+		delete(metadata, k)
+	}
+	return metadata, nil
+}
+
+// requireUpdate returns an error if an update request changes nothing. The
+// api.Update*Request types leave nil fields unchanged, so a request whose
+// pointer, slice and map fields, including Unset, are all nil is empty.
+func requireUpdate(request interface{}) error {
+	v := reflect.ValueOf(request)
+	for i := 0; i < v.NumField(); i++ {
+		switch field := v.Field(i); field.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if !field.IsNil() {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("nothing to update, see --help for the available settings")
+}
+
+// editList returns list with add appended and remove dropped, keeping the
+// original order and skipping duplicates. The result is never nil.
+func editList(list, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, item := range remove {
+		removed[item] = true
+	}
+
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, item := range append(append([]string{}, list...), add...) {
+		if removed[item] || seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
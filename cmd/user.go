@@ -10,9 +10,12 @@ var userCmd = &cobra.Command{
 	Use:   "user",
 	Short: "Manage users and their information",
 	Long: `User management commands allow you to:
+- List users page by page, filtered by role, team or email
+- Create, update and delete users
 - View user information and settings
 - List user's teams and API keys
-- View user's spend and budget information`,
+- View user's spend and budget information
+- Onboard and offboard users`,
 }
 
 func init() {
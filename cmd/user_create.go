@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a user",
+	Long: `Create a user with an email and/or a user ID. Without --user-id the server
+picks the ID. No key is generated for the new user unless --with-key is set.
+
+Example:
+  # Create a user by email
+  navigatorctl user create --email jdoe@example.com
+
+  # Create an admin with a budget and a model allow list
+  navigatorctl user create --user-id jdoe --email jdoe@example.com --role proxy_admin \
+    --max-budget 100 --models gpt-4.1,gpt-4.1-mini`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := getOutputFormat(cmd)
+		role, _ := cmd.Flags().GetString("role")
+		alias, _ := cmd.Flags().GetString("alias")
+		models, _ := cmd.Flags().GetStringSlice("models")
+		withKey, _ := cmd.Flags().GetBool("with-key")
+
+		request := api.CreateUserRequest{
+			UserID:        viper.GetString("user.id"),
+			UserEmail:     viper.GetString("user.email"),
+			UserAlias:     alias,
+			UserRole:      role,
+			Models:        models,
+			Metadata:      getMetadataFlag(cmd, "metadata"),
+			AutoCreateKey: withKey,
+		}
+		if request.UserID == "" && request.UserEmail == "" {
+			fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+			os.Exit(1)
+		}
+		if role != "" {
+			if err := validateUserRole(role); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("max-budget") {
+			maxBudget, _ := cmd.Flags().GetFloat64("max-budget")
+			request.MaxBudget = &maxBudget
+		}
+
+		client := getAPIClient()
+		created, err := client.CreateUserContext(cmd.Context(), request)
+		if err != nil {
+			handleError("Error creating user", err)
+		}
+
+		if format == "table" {
+			fmt.Printf("Successfully created user %s\n", created.UserID)
+			if created.Key != "" {
+				fmt.Printf("Key: %s\n", created.Key)
+				fmt.Println("Store this key securely; it cannot be shown again.")
+			}
+		}
+		showUser(cmd, client, created.UserID, format)
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userCreateCmd)
+
+	userCreateCmd.Flags().String("role", "", "User role: "+strings.Join(api.UserRoles, ", "))
+	userCreateCmd.Flags().String("alias", "", "Display name for the user")
+	userCreateCmd.Flags().StringSlice("models", nil, "Models the user may use (default all)")
+	userCreateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	userCreateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs")
+	userCreateCmd.Flags().Bool("with-key", false, "Also generate a key for the user")
+}
+
+// validateUserRole checks role against the roles the proxy knows
+func validateUserRole(role string) error {
+	for _, known := range api.UserRoles {
+		if role == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown role %q, expected one of %s", role, strings.Join(api.UserRoles, ", "))
+}
+
+// showUser fetches a user and prints it like user info
+func showUser(cmd *cobra.Command, client *api.Client, userID, format string) {
+	response, err := client.GetUserInfoContext(cmd.Context(), userID)
+	if err != nil {
+		handleError("Error getting user info", err)
+	}
+
+	switch format {
+	case "json":
+		outputUserInfoJSON(response)
+	case "table":
+		outputUserInfoTable(response)
+	}
+}
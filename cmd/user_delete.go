@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a user",
+	Long: `Delete a user. The user is shown first and must be confirmed; --yes skips
+the prompt for scripts. To revoke the user's keys and team memberships in a
+controlled way, run user offboard first.
+
+Example:
+  navigatorctl user delete --email jdoe@example.com
+
+  # Without a prompt
+  navigatorctl user delete --user-id jdoe --yes`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		identifier := getUserIdentifier(cmd)
+		if identifier == "" {
+			fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+			os.Exit(1)
+		}
+		format := getOutputFormat(cmd)
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		client := getAPIClient()
+		// Resolve emails through FindUserByEmail; /user/info may ignore the
+		// email and answer for another user
+		userID := resolveUserID(cmd, client, identifier)
+		user, err := client.GetUserInfoContext(cmd.Context(), userID)
+		if err != nil {
+			handleError("Error getting user info", err)
+		}
+		if user.UserInfo == nil {
+			fmt.Fprintf(os.Stderr, "Error: user %s not found\n", userID)
+			os.Exit(1)
+		}
+
+		if format == "table" {
+			fmt.Println("The following user will be deleted:")
+			outputUserInfoTable(user)
+		}
+		requireConfirmation(yes, fmt.Sprintf("Delete user %s?", userID))
+
+		if err := client.DeleteUserContext(cmd.Context(), userID); err != nil {
+			handleError("Error deleting user", err)
+		}
+
+		switch format {
+		case "json":
+			outputUserInfoJSON(user)
+		case "table":
+			fmt.Printf("Successfully deleted user %s\n", userID)
+		}
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userDeleteCmd)
+
+	userDeleteCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
@@ -4,12 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var userInfoCmd = &cobra.Command{
@@ -47,20 +47,8 @@ func showUserInfo(cmd *cobra.Command, args []string) {
 
 	format := getOutputFormat(cmd)
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
-
-	response, err := client.GetUserInfo(identifier)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user info: %v\n", err)
-		os.Exit(1)
-	}
-
-	switch format {
-	case "json":
-		outputUserInfoJSON(response)
-	case "table":
-		outputUserInfoTable(response)
-	}
+	client := getAPIClient()
+	showUser(cmd, client, resolveUserID(cmd, client, identifier), format)
 }
 
 func outputUserInfoJSON(response *api.UserResponse) {
@@ -98,7 +86,11 @@ func outputUserInfoTable(response *api.UserResponse) {
 
 	table.Append([]string{"User ID", response.UserInfo.UserID})
 	table.Append([]string{"Email", getOrDefault(response.UserInfo.UserEmail, "-")})
+	table.Append([]string{"Alias", getOrDefault(response.UserInfo.UserAlias, "-")})
 	table.Append([]string{"Role", response.UserInfo.UserRole})
+	if len(response.UserInfo.Models) > 0 {
+		table.Append([]string{"Models", strings.Join(response.UserInfo.Models, ", ")})
+	}
 	table.Append([]string{"Spend", fmt.Sprintf("$%.2f", response.UserInfo.Spend)})
 	if response.UserInfo.MaxBudget > 0 {
 		table.Append([]string{"Max Budget", fmt.Sprintf("$%.2f", response.UserInfo.MaxBudget)})
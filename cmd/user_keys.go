@@ -10,7 +10,6 @@ import (
 	"github.com/ncecere/navigatorctl/pkg/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var userKeysCmd = &cobra.Command{
@@ -47,12 +46,12 @@ func showUserKeys(cmd *cobra.Command, args []string) {
 
 	format := getOutputFormat(cmd)
 
-	client := api.NewClient(viper.GetString("api.url"), viper.GetString("api.key"))
+	client := getAPIClient()
 
-	response, err := client.GetUserInfo(identifier)
+	userID := resolveUserID(cmd, client, identifier)
+	response, err := client.GetUserInfoContext(cmd.Context(), userID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user keys: %v\n", err)
-		os.Exit(1)
+		handleError("Error getting user keys", err)
 	}
 
 	switch format {
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users",
+	Long: `List users with their role, spend and budget, optionally filtered by role,
+team (ID or alias) and a case-insensitive email substring.
+
+Example:
+  # First page of users
+  navigatorctl user list
+
+  # Admins whose email contains @example.com
+  navigatorctl user list --role proxy_admin --email-contains @example.com
+
+  # Every member of a team
+  navigatorctl user list --team CLINE --all
+
+  # A specific page
+  navigatorctl user list --page 2 --page-size 50
+
+  # Every user as JSON
+  navigatorctl user list --all --output json`,
+	Run: listUsers,
+}
+
+func init() {
+	addPaginationFlags(userListCmd)
+	userCmd.AddCommand(userListCmd)
+
+	userListCmd.Flags().String("role", "", "Only list users with this role, e.g. internal_user")
+	userListCmd.Flags().String("team", "", "Only list members of this team (ID or alias)")
+	userListCmd.Flags().String("email-contains", "", "Only list users whose email contains this text")
+}
+
+func listUsers(cmd *cobra.Command, args []string) {
+	format := getOutputFormat(cmd)
+	page, pageSize, all := getPagination(cmd)
+	role, _ := cmd.Flags().GetString("role")
+	team, _ := cmd.Flags().GetString("team")
+	email, _ := cmd.Flags().GetString("email-contains")
+
+	if role != "" {
+		if err := validateUserRole(role); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	client := getAPIClient()
+
+	result, err := client.ListUsersContext(cmd.Context(), api.UserListOptions{
+		Role:     role,
+		TeamID:   team,
+		Email:    email,
+		Page:     page,
+		PageSize: pageSize,
+		AllPages: all,
+	})
+	if err != nil {
+		handleError("Error listing users", err)
+	}
+
+	switch format {
+	case "json":
+		outputUserListJSON(result.Users)
+	case "table":
+		outputUserListTable(result.Users)
+	}
+
+	if !all {
+		printPageHint(page, result.TotalPages, result.Total)
+	}
+}
+
+func outputUserListJSON(users []api.UserInfo) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(users); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func outputUserListTable(users []api.UserInfo) {
+	if len(users) == 0 {
+		fmt.Println("No users found")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"User ID", "Email", "Role", "Spend", "Max Budget", "Created"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+
+	for _, user := range users {
+		maxBudget := "-"
+		if user.MaxBudget > 0 {
+			maxBudget = fmt.Sprintf("$%.2f", user.MaxBudget)
+		}
+
+		created := user.CreatedAt
+		if t, err := time.Parse(time.RFC3339, user.CreatedAt); err == nil {
+			created = t.Format("2006-01-02 15:04:05")
+		}
+
+		table.Append([]string{
+			user.UserID,
+			getOrDefault(user.UserEmail, "-"),
+			getOrDefault(user.UserRole, "-"),
+			fmt.Sprintf("$%.2f", user.Spend),
+			maxBudget,
+			created,
+		})
+	}
+
+	table.Render()
+}
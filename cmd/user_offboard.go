@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// Offboarding key policies
+const (
+	keyPolicyBlock    = "block"
+	keyPolicyDelete   = "delete"
+	keyPolicyTransfer = "transfer"
+)
+
+// offboardAction is a single step of an offboarding, as recorded in the
+// report. Status is planned, done, failed or skipped.
+type offboardAction struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Detail string `json:"detail,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	run func(ctx context.Context) error
+}
+
+// offboardReport is the audit record written by user offboard
+type offboardReport struct {
+	UserID     string           `json:"user_id"`
+	UserEmail  string           `json:"user_email,omitempty"`
+	DryRun     bool             `json:"dry_run"`
+	KeyPolicy  string           `json:"key_policy"`
+	Successor  string           `json:"successor,omitempty"`
+	StartedAt  string           `json:"started_at"`
+	FinishedAt string           `json:"finished_at,omitempty"`
+	Actions    []offboardAction `json:"actions"`
+}
+
+var userOffboardCmd = &cobra.Command{
+	Use:   "offboard",
+	Short: "Remove a user's access",
+	Long: `Offboard a user: remove them from every team, block, delete or transfer
+their keys and optionally delete the user. Every step is recorded in a JSON
+report for the audit trail, written with --report or printed with --output
+json. Failed steps are reported without stopping the remaining ones.
+
+Keys are handled according to --keys:
+- block (default): keys stay but stop working
+- delete: keys are deleted
+- transfer: keys are given to --successor, staying in their teams
+
+Keys are handled before team memberships are removed. --delete-user deletes
+the user last.
+
+Example:
+  # Show what would happen
+  navigatorctl user offboard --email jdoe@example.com --dry-run
+
+  # Hand keys over to a colleague and keep an audit record
+  navigatorctl user offboard --email jdoe@example.com --keys transfer --successor asmith@example.com --report offboard-jdoe.json
+
+  # Remove everything
+  navigatorctl user offboard --user-id jdoe --keys delete --delete-user --yes`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		identifier := getUserIdentifier(cmd)
+		if identifier == "" {
+			fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+			os.Exit(1)
+		}
+		format := getOutputFormat(cmd)
+		keyPolicy, _ := cmd.Flags().GetString("keys")
+		successor, _ := cmd.Flags().GetString("successor")
+		deleteUser, _ := cmd.Flags().GetBool("delete-user")
+		reportPath, _ := cmd.Flags().GetString("report")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if successor != "" && !cmd.Flags().Changed("keys") {
+			keyPolicy = keyPolicyTransfer
+		}
+		switch keyPolicy {
+		case keyPolicyBlock, keyPolicyDelete:
+			if successor != "" {
+				fmt.Fprintf(os.Stderr, "Error: --successor only applies to --keys %s\n", keyPolicyTransfer)
+				os.Exit(1)
+			}
+		case keyPolicyTransfer:
+			if successor == "" {
+				fmt.Fprintf(os.Stderr, "Error: --keys %s requires --successor\n", keyPolicyTransfer)
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Error: invalid --keys %q, must be block, delete or transfer\n", keyPolicy)
+			os.Exit(1)
+		}
+		if reportPath != "" {
+			// The report is written after the changes are made, so check now
+			// that it can be
+			if _, err := os.Lstat(reportPath); err == nil {
+				fmt.Fprintf(os.Stderr, "Error: report file %s already exists\n", reportPath)
+				os.Exit(1)
+			}
+		}
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+
+		userID := resolveUserID(cmd, client, identifier)
+		user, err := client.GetUserInfoContext(ctx, userID)
+		if err != nil {
+			handleError("Error getting user info", err)
+		}
+		keys, err := client.ListAllKeysContext(ctx, api.KeyListOptions{UserID: userID})
+		if err != nil {
+			handleError("Error listing user keys", err)
+		}
+
+		var successorID string
+		if successor != "" {
+			successorID = resolveUserID(cmd, client, successor)
+			if successorID == userID {
+				fmt.Fprintln(os.Stderr, "Error: the successor is the user being offboarded")
+				os.Exit(1)
+			}
+		}
+
+		report := offboardReport{
+			UserID:    userID,
+			DryRun:    dryRun,
+			KeyPolicy: keyPolicy,
+			Successor: successorID,
+			StartedAt: time.Now().UTC().Format(time.RFC3339),
+			Actions:   []offboardAction{},
+		}
+		if user.UserInfo != nil {
+			report.UserEmail = user.UserInfo.UserEmail
+		}
+
+		for _, key := range keys {
+			token := key.Token
+			target := fmt.Sprintf("key %s (%s)", maskKey(key.KeyName), getOrDefault(key.KeyAlias, "-"))
+			action := offboardAction{Target: target, Detail: "token " + token}
+			switch keyPolicy {
+			case keyPolicyBlock:
+				action.Action = "block-key"
+				action.run = func(ctx context.Context) error {
+					_, err := client.BlockKeyContext(ctx, token)
+					return err
+				}
+			case keyPolicyDelete:
+				action.Action = "delete-key"
+				action.run = func(ctx context.Context) error {
+					_, err := client.DeleteKeysContext(ctx, []string{token})
+					return err
+				}
+			case keyPolicyTransfer:
+				action.Action = "transfer-key"
+				action.Detail += " to user " + successorID
+				action.run = func(ctx context.Context) error {
+					_, err := client.UpdateKeyContext(ctx, api.UpdateKeyRequest{Key: token, UserID: &successorID})
+					return err
+				}
+			}
+			report.Actions = append(report.Actions, action)
+		}
+
+		for _, team := range userTeams(user) {
+			teamID := team.TeamID
+			report.Actions = append(report.Actions, offboardAction{
+				Action: "remove-from-team",
+				Target: fmt.Sprintf("team %s (%s)", getOrDefault(team.TeamAlias, "-"), teamID),
+				run: func(ctx context.Context) error {
+					_, err := client.RemoveTeamMemberContext(ctx, teamID, api.TeamMember{UserID: userID})
+					return err
+				},
+			})
+		}
+
+		if deleteUser {
+			report.Actions = append(report.Actions, offboardAction{
+				Action: "delete-user",
+				Target: "user " + userID,
+				run: func(ctx context.Context) error {
+					return client.DeleteUserContext(ctx, userID)
+				},
+			})
+		}
+
+		plan := os.Stdout
+		if format == "json" {
+			plan = os.Stderr
+		}
+		fmt.Fprintf(plan, "Offboarding user %s (%s):\n", userID, getOrDefault(report.UserEmail, "-"))
+		for _, action := range report.Actions {
+			fmt.Fprintf(plan, "  %s %s\n", action.Action, action.Target)
+		}
+		if len(report.Actions) == 0 {
+			fmt.Fprintln(plan, "  nothing to do")
+		}
+
+		if dryRun || len(report.Actions) == 0 {
+			for i := range report.Actions {
+				report.Actions[i].Status = "planned"
+			}
+		} else {
+			requireConfirmation(yes, fmt.Sprintf("Offboard user %s?", userID))
+			for i := range report.Actions {
+				action := &report.Actions[i]
+				if ctx.Err() != nil {
+					action.Status = "skipped"
+					continue
+				}
+				if err := action.run(ctx); err != nil {
+					action.Status = "failed"
+					action.Error = err.Error()
+					continue
+				}
+				action.Status = "done"
+			}
+		}
+		report.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+
+		if reportPath != "" {
+			if err := writeJSONFile(reportPath, report); err != nil {
+				// The changes are made, so print the report rather than lose it
+				fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+				encoder := json.NewEncoder(os.Stderr)
+				encoder.SetIndent("", "  ")
+				encoder.Encode(report)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Report written to %s\n", reportPath)
+		}
+
+		failed := 0
+		for _, action := range report.Actions {
+			if action.Status == "failed" {
+				failed++
+			}
+		}
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			if !dryRun && len(report.Actions) > 0 {
+				for _, action := range report.Actions {
+					if action.Status == "failed" {
+						fmt.Fprintf(os.Stderr, "Error: %s %s: %s\n", action.Action, action.Target, action.Error)
+					}
+				}
+				fmt.Printf("Completed %d of %d steps\n", len(report.Actions)-failed, len(report.Actions))
+			}
+		}
+
+		if ctx.Err() != nil {
+			handleError("Error offboarding user", ctx.Err())
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userOffboardCmd)
+
+	userOffboardCmd.Flags().String("keys", keyPolicyBlock, "What to do with the user's keys: block, delete or transfer")
+	userOffboardCmd.Flags().String("successor", "", "User ID or email to transfer the keys to (implies --keys transfer)")
+	userOffboardCmd.Flags().Bool("delete-user", false, "Delete the user after removing their access")
+	userOffboardCmd.Flags().String("report", "", "Write the JSON report of every action to this new file (mode 0600)")
+	userOffboardCmd.Flags().Bool("dry-run", false, "Show the plan without changing anything")
+	userOffboardCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+// userTeams returns the teams a user belongs to, from the team objects and
+// the team IDs on the user record
+func userTeams(user *api.UserResponse) []api.TeamInfo {
+	var teams []api.TeamInfo
+	seen := make(map[string]bool)
+	for _, team := range user.Teams {
+		if team.TeamID != "" && !seen[team.TeamID] {
+			seen[team.TeamID] = true
+			teams = append(teams, team)
+		}
+	}
+	if user.UserInfo != nil {
+		for _, teamID := range user.UserInfo.Teams {
+			if !seen[teamID] {
+				seen[teamID] = true
+				teams = append(teams, api.TeamInfo{TeamID: teamID})
+			}
+		}
+	}
+	return teams
+}
+
+// writeJSONFile writes v as indented JSON to path, readable only by the
+// current user since reports may contain key tokens
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(path, append(data, '\n'))
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultKeyAliasTemplate names onboarding keys when the config has no
+// onboard.key.alias template
+const defaultKeyAliasTemplate = "{{.User}}-{{.Team}}"
+
+// onboardKey is a personal key in the credentials bundle. Secret is empty for
+// keys issued by an earlier run, whose secret cannot be shown again.
+type onboardKey struct {
+	Team     string `json:"team"`
+	TeamID   string `json:"team_id"`
+	KeyAlias string `json:"key_alias"`
+	Token    string `json:"token"`
+	Secret   string `json:"secret,omitempty"`
+	Existing bool   `json:"existing,omitempty"`
+}
+
+// onboardBundle is the credentials bundle printed by user onboard
+type onboardBundle struct {
+	UserID    string       `json:"user_id"`
+	UserEmail string       `json:"user_email"`
+	BaseURL   string       `json:"base_url"`
+	Teams     []string     `json:"teams"`
+	Keys      []onboardKey `json:"keys"`
+}
+
+var userOnboardCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Give a new user access to teams",
+	Long: `Onboard a user by email: create the user if missing, add them to each team
+and, with --issue-keys, generate a personal key per team. Re-running the
+command only performs the missing steps, so a partially onboarded user can be
+completed safely. Each step is reported on stderr.
+
+Key settings come from the onboard.key section of the config file:
+
+  onboard:
+    key:
+      alias: "{{.User}}-{{.Team}}"   # .User is the email's local part, .Email and .UserID also work
+      models: [gpt-4.1, gpt-4.1-mini]
+      max_budget: 25
+      budget_duration: 30d
+      duration: 90d
+      metadata:
+        onboarded_by: navigatorctl
+
+The credentials bundle is printed as JSON or as .env lines (--bundle-format)
+or written to a new file readable only by you (--bundle-file); an existing
+file is never overwritten. Keys issued by an earlier run are listed without
+their secret, which cannot be shown again.
+
+Example:
+  navigatorctl user onboard --email jdoe@example.com --team CHAT --team CLINE --role user
+
+  # Issue keys and write them to a .env file
+  navigatorctl user onboard --email jdoe@example.com --team CHAT --issue-keys --bundle-format env --bundle-file jdoe.env`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		email, _ := cmd.Flags().GetString("email")
+		teams, _ := cmd.Flags().GetStringArray("team")
+		role, _ := cmd.Flags().GetString("role")
+		issueKeys, _ := cmd.Flags().GetBool("issue-keys")
+		bundleFormat, _ := cmd.Flags().GetString("bundle-format")
+		bundleFile, _ := cmd.Flags().GetString("bundle-file")
+
+		if email == "" || !strings.Contains(email, "@") {
+			fmt.Fprintln(os.Stderr, "Error: --email is required")
+			os.Exit(1)
+		}
+		if role != "admin" && role != "user" {
+			fmt.Fprintln(os.Stderr, "Error: role must be either 'admin' or 'user'")
+			os.Exit(1)
+		}
+		if bundleFormat != "json" && bundleFormat != "env" {
+			fmt.Fprintf(os.Stderr, "Error: invalid --bundle-format %q, must be json or env\n", bundleFormat)
+			os.Exit(1)
+		}
+
+		aliasTemplate, err := template.New("alias").Option("missingkey=error").Parse(getOrDefault(viper.GetString("onboard.key.alias"), defaultKeyAliasTemplate))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid onboard.key.alias template: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		client := getAPIClient()
+
+		// User
+		var userID string
+		user, err := client.FindUserByEmailContext(ctx, email)
+		switch {
+		case err == nil:
+			userID = user.UserID
+			fmt.Fprintf(os.Stderr, "User %s already exists (%s)\n", email, userID)
+		case errors.Is(err, api.ErrUserNotFound):
+			created, err := client.CreateUserContext(ctx, api.CreateUserRequest{UserEmail: email})
+			if err != nil {
+				handleError("Error creating user", err)
+			}
+			userID = created.UserID
+			fmt.Fprintf(os.Stderr, "Created user %s (%s)\n", email, userID)
+		default:
+			handleError("Error looking up user", err)
+		}
+
+		bundle := onboardBundle{
+			UserID:    userID,
+			UserEmail: email,
+			BaseURL:   viper.GetString("api.url"),
+			Teams:     []string{},
+			Keys:      []onboardKey{},
+		}
+
+		// Secrets issued so far cannot be shown again, so print the bundle
+		// before giving up once a key was issued
+		fail := func(msg string, err error) {
+			for _, key := range bundle.Keys {
+				if key.Secret != "" {
+					printOnboardBundle(bundle, bundleFormat, bundleFile)
+					break
+				}
+			}
+			handleError(msg, err)
+		}
+
+		var existingKeys []api.KeyObject
+		if issueKeys {
+			existingKeys, err = client.ListAllKeysContext(ctx, api.KeyListOptions{UserID: userID})
+			if err != nil {
+				handleError("Error listing user keys", err)
+			}
+		}
+
+		for _, identifier := range teams {
+			// Team membership
+			team, err := client.GetTeamInfoContext(ctx, identifier)
+			if err != nil {
+				fail(fmt.Sprintf("Error getting team %s", identifier), err)
+			}
+			teamName := getOrDefault(team.TeamAlias, team.TeamID)
+			bundle.Teams = append(bundle.Teams, teamName)
+
+			var membership *api.TeamMemberDetails
+			for i := range team.Members {
+				if team.Members[i].UserID == userID {
+					membership = &team.Members[i]
+				}
+			}
+			if membership != nil {
+				fmt.Fprintf(os.Stderr, "Already a member of team %s as %s\n", teamName, membership.Role)
+			} else {
+				member := api.TeamMember{UserID: userID, UserEmail: email, Role: role}
+				if _, err := client.AddTeamMemberContext(ctx, team.TeamID, member); err != nil {
+					fail(fmt.Sprintf("Error adding user to team %s", teamName), err)
+				}
+				fmt.Fprintf(os.Stderr, "Added to team %s as %s\n", teamName, role)
+			}
+
+			if !issueKeys {
+				continue
+			}
+
+			// Personal key
+			var alias bytes.Buffer
+			data := map[string]string{"User": strings.SplitN(email, "@", 2)[0], "Email": email, "UserID": userID, "Team": teamName}
+			if err := aliasTemplate.Execute(&alias, data); err != nil {
+				fail("Error: invalid onboard.key.alias template", err)
+			}
+
+			key := onboardKey{Team: teamName, TeamID: team.TeamID, KeyAlias: alias.String()}
+			for _, existing := range existingKeys {
+				if existing.UserID == userID && existing.TeamID == team.TeamID && existing.KeyAlias == key.KeyAlias {
+					key.Token = existing.Token
+					key.Existing = true
+				}
+			}
+			if key.Existing {
+				fmt.Fprintf(os.Stderr, "Key %s already exists in team %s\n", key.KeyAlias, teamName)
+			} else {
+				request := onboardKeyRequest()
+				request.KeyAlias = key.KeyAlias
+				request.TeamID = team.TeamID
+				request.UserID = userID
+				generated, err := client.GenerateKeyContext(ctx, request)
+				if err != nil {
+					fail(fmt.Sprintf("Error generating key for team %s", teamName), err)
+				}
+				key.Token = generated.HashedToken()
+				key.Secret = generated.Key
+				fmt.Fprintf(os.Stderr, "Issued key %s in team %s\n", key.KeyAlias, teamName)
+			}
+			bundle.Keys = append(bundle.Keys, key)
+		}
+
+		printOnboardBundle(bundle, bundleFormat, bundleFile)
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userOnboardCmd)
+
+	userOnboardCmd.Flags().StringArray("team", nil, "Team ID or alias to add the user to (repeatable)")
+	userOnboardCmd.Flags().String("role", "user", "Role in each team (admin/user)")
+	userOnboardCmd.Flags().Bool("issue-keys", false, "Issue a personal key in each team using the onboard.key config defaults")
+	userOnboardCmd.Flags().String("bundle-format", "json", "Credentials bundle format: json or env")
+	userOnboardCmd.Flags().String("bundle-file", "", "Write the credentials bundle to this new file (mode 0600) instead of stdout")
+}
+
+// onboardKeyRequest builds a key request from the onboard.key config section
+func onboardKeyRequest() api.GenerateKeyRequest {
+	request := api.GenerateKeyRequest{
+		Models:         viper.GetStringSlice("onboard.key.models"),
+		BudgetDuration: viper.GetString("onboard.key.budget_duration"),
+		Duration:       viper.GetString("onboard.key.duration"),
+	}
+	if viper.IsSet("onboard.key.max_budget") {
+		maxBudget := viper.GetFloat64("onboard.key.max_budget")
+		request.MaxBudget = &maxBudget
+	}
+	if viper.IsSet("onboard.key.tpm_limit") {
+		tpmLimit := viper.GetInt64("onboard.key.tpm_limit")
+		request.TPMLimit = &tpmLimit
+	}
+	if viper.IsSet("onboard.key.rpm_limit") {
+		rpmLimit := viper.GetInt64("onboard.key.rpm_limit")
+		request.RPMLimit = &rpmLimit
+	}
+	if metadata := viper.GetStringMap("onboard.key.metadata"); len(metadata) > 0 {
+		request.Metadata = metadata
+	}
+	return request
+}
+
+// envName turns a team name into an environment variable suffix
+var envName = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// printOnboardBundle prints the credentials bundle, or writes it to path
+func printOnboardBundle(bundle onboardBundle, format, path string) {
+	var out bytes.Buffer
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(&out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(bundle); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "env":
+		fmt.Fprintf(&out, "# Credentials for %s (%s)\n", bundle.UserEmail, bundle.UserID)
+		fmt.Fprintf(&out, "LITELLM_BASE_URL=%s\n", bundle.BaseURL)
+		for _, key := range bundle.Keys {
+			name := "LITELLM_API_KEY_" + strings.Trim(envName.ReplaceAllString(strings.ToUpper(key.Team), "_"), "_")
+			if key.Secret == "" {
+				fmt.Fprintf(&out, "# %s: key %s was issued earlier, its secret cannot be shown again\n", name, key.KeyAlias)
+				continue
+			}
+			fmt.Fprintf(&out, "%s=%s\n", name, key.Secret)
+		}
+	}
+
+	if path == "" {
+		out.WriteTo(os.Stdout)
+		return
+	}
+	if err := writePrivateFile(path, out.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing credentials bundle: %v\n", err)
+		fmt.Fprint(os.Stderr, out.String())
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Credentials written to %s\n", path)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+var userUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a user",
+	Long: `Update the role, budget, models, alias or metadata of a user and show what
+changed. Only the settings given as flags are changed.
+
+Models can be replaced with --models or edited with --add-model and
+--remove-model. Metadata given with --metadata is merged into the existing
+metadata unless --replace-metadata is set. --unset-max-budget removes the
+user's budget.
+
+Example:
+  # Promote a user to admin
+  navigatorctl user update --email jdoe@example.com --role proxy_admin
+
+  # Raise the budget and allow one more model
+  navigatorctl user update --user-id jdoe --max-budget 200 --add-model gpt-4.1`,
+	Annotations: mutating,
+	Run: func(cmd *cobra.Command, args []string) {
+		identifier := getUserIdentifier(cmd)
+		if identifier == "" {
+			fmt.Fprintln(os.Stderr, "Error: either --user-id or --email is required")
+			os.Exit(1)
+		}
+		format := getOutputFormat(cmd)
+
+		client := getAPIClient()
+		// Resolve emails through FindUserByEmail; /user/info may ignore the
+		// email and answer for another user
+		userID := resolveUserID(cmd, client, identifier)
+		before, err := client.GetUserInfoContext(cmd.Context(), userID)
+		if err != nil {
+			handleError("Error getting user info", err)
+		}
+		if before.UserInfo == nil {
+			fmt.Fprintf(os.Stderr, "Error: user %s not found\n", userID)
+			os.Exit(1)
+		}
+
+		request, err := buildUserUpdate(cmd, before.UserInfo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := client.UpdateUserContext(cmd.Context(), request); err != nil {
+			handleError("Error updating user", err)
+		}
+
+		after, err := client.GetUserInfoContext(cmd.Context(), request.UserID)
+		if err != nil {
+			handleError("Error getting user info", err)
+		}
+
+		changes, err := diff.Fields(before.UserInfo, after.UserInfo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing user: %v\n", err)
+			os.Exit(1)
+		}
+		changes = withoutField(changes, "updated_at")
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			result := struct {
+				Changes []diff.Change     `json:"changes"`
+				User    *api.UserResponse `json:"user"`
+			}{changes, after}
+			if err := encoder.Encode(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			fmt.Printf("Successfully updated user %s\n", request.UserID)
+			if len(changes) == 0 {
+				fmt.Println("No changes")
+			}
+			for _, change := range changes {
+				fmt.Println("  " + change.String())
+			}
+			fmt.Println()
+			outputUserInfoTable(after)
+		}
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userUpdateCmd)
+
+	userUpdateCmd.Flags().String("role", "", "New role: "+strings.Join(api.UserRoles, ", "))
+	userUpdateCmd.Flags().String("alias", "", "New display name for the user")
+	userUpdateCmd.Flags().Float64("max-budget", 0, "Maximum spend in USD")
+	userUpdateCmd.Flags().Bool("unset-max-budget", false, "Remove the user's budget")
+	userUpdateCmd.Flags().StringSlice("models", nil, "Replace the models the user may use")
+	userUpdateCmd.Flags().StringSlice("add-model", nil, "Allow the user to use these models")
+	userUpdateCmd.Flags().StringSlice("remove-model", nil, "Stop the user from using these models")
+	userUpdateCmd.Flags().StringToString("metadata", nil, "Metadata as key=value pairs, merged into the existing metadata")
+	userUpdateCmd.Flags().Bool("replace-metadata", false, "Replace the existing metadata with --metadata instead of merging")
+	userUpdateCmd.Flags().StringSlice("remove-metadata", nil, "Metadata keys to remove")
+}
+
+// buildUserUpdate turns the command's flags into an update request for user,
+// applying incremental model and metadata edits to its current values
+func buildUserUpdate(cmd *cobra.Command, user *api.UserInfo) (api.UpdateUserRequest, error) {
+	flags := cmd.Flags()
+	request := api.UpdateUserRequest{UserID: user.UserID}
+
+	if flags.Changed("role") {
+		role, _ := flags.GetString("role")
+		if err := validateUserRole(role); err != nil {
+			return request, err
+		}
+		request.UserRole = &role
+	}
+	if flags.Changed("alias") {
+		alias, _ := flags.GetString("alias")
+		request.UserAlias = &alias
+	}
+	if unset, _ := flags.GetBool("unset-max-budget"); unset {
+		if flags.Changed("max-budget") {
+			return request, fmt.Errorf("--max-budget cannot be combined with --unset-max-budget")
+		}
+		request.Unset = append(request.Unset, "max_budget")
+	}
+	if flags.Changed("max-budget") {
+		maxBudget, _ := flags.GetFloat64("max-budget")
+		request.MaxBudget = &maxBudget
+	}
+
+	request.Models = mergeModels(cmd, user.Models)
+	metadata, err := mergeMetadata(cmd, user.Metadata)
+	if err != nil {
+		return request, err
+	}
+	request.Metadata = metadata
+
+	return request, requireUpdate(request)
+}
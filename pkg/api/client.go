@@ -2,9 +2,12 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -14,6 +17,9 @@ type Client struct {
 	BaseURL    string
 	APIKey     string
 	HTTPClient *http.Client
+	Retry      RetryPolicy
+	// Verbose, when set, receives a line for every request attempt
+	Verbose io.Writer
 }
 
 // NewClient creates a new API client
@@ -24,363 +30,131 @@ func NewClient(baseURL, apiKey string) *Client {
 		HTTPClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		Retry: DefaultRetryPolicy(),
 	}
 }
 
-// resolveTeamIdentifier gets team ID from either ID or alias
-func (c *Client) resolveTeamIdentifier(identifier string) (string, error) {
-	// If it looks like a UUID, use it directly
-	if len(identifier) == 36 && identifier[8] == '-' {
-		return identifier, nil
+// do sends a request to the API and decodes the JSON response into out.
+// query and out may be nil; a non-nil body is sent as JSON. Transient
+// failures are retried according to c.Retry, and any non-2xx response is
+// returned as an *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	endpoint := strings.TrimRight(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
 	}
 
-	// Otherwise, try to find the team by alias
-	teams, err := c.ListTeams()
-	if err != nil {
-		return "", err
-	}
-
-	for _, team := range teams {
-		if team.TeamAlias == identifier {
-			return team.TeamID, nil
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
 		}
 	}
 
-	return "", fmt.Errorf("team not found: %s", identifier)
-}
-
-// ListTeamMembers gets all members in a team
-func (c *Client) ListTeamMembers(identifier string) ([]TeamMember, error) {
-	teamID, err := c.resolveTeamIdentifier(identifier)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/team/info?team_id=%s", c.BaseURL, teamID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+	attempts := 1
+	if c.Retry.allows(method) {
+		attempts += c.Retry.MaxRetries
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
+	for attempt := 1; ; attempt++ {
+		var reqBody io.Reader
+		if data != nil {
+			reqBody = bytes.NewReader(data)
 		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var response TeamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	return response.TeamInfo.MembersWithRoles, nil
-}
-
-// AddTeamMember adds a new member to a team
-func (c *Client) AddTeamMember(identifier string, member TeamMember) (*TeamResponse, error) {
-	teamID, err := c.resolveTeamIdentifier(identifier)
-	if err != nil {
-		return nil, err
-	}
-
-	request := AddMemberRequest{
-		Member: []TeamMember{member},
-		TeamID: teamID,
-	}
-
-	url := fmt.Sprintf("%s/team/member_add", c.BaseURL)
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling request: %w", err)
-	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
 		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var response TeamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	return &response, nil
-}
 
-// RemoveTeamMember removes a member from a team
-func (c *Client) RemoveTeamMember(identifier string, member TeamMember) (*TeamResponse, error) {
-	teamID, err := c.resolveTeamIdentifier(identifier)
-	if err != nil {
-		return nil, err
-	}
-
-	request := RemoveMemberRequest{
-		UserID:    member.UserID,
-		UserEmail: member.UserEmail,
-		TeamID:    teamID,
-	}
-
-	url := fmt.Sprintf("%s/team/member_delete", c.BaseURL)
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
+		req.Header.Set("x-litellm-api-key", c.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		if attempt < attempts && shouldRetry(ctx, resp, err) {
+			delay, ok := c.Retry.backoff(attempt, resp)
+			switch {
+			case !ok:
+				c.logf("%s %s -> %s (attempt %d/%d, %s), server asked to wait %s, longer than the maximum backoff", method, path, resp.Status, attempt, attempts, elapsed, delay)
+				return c.decodeResponse(resp, method, path, out)
+			case err != nil:
+				c.logf("%s %s failed: %v (attempt %d/%d, %s), retrying in %s", method, path, err, attempt, attempts, elapsed, delay)
+			default:
+				c.logf("%s %s -> %s (attempt %d/%d, %s), retrying in %s", method, path, resp.Status, attempt, attempts, elapsed, delay)
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return fmt.Errorf("making request: %w", err)
+			}
+			continue
 		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var response TeamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	return &response, nil
-}
 
-// GetKeyInfo gets detailed information about a specific key
-func (c *Client) GetKeyInfo(keyID string) (*KeyResponse, error) {
-	url := fmt.Sprintf("%s/key/info?key=%s", c.BaseURL, keyID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
+		if err != nil {
+			c.logf("%s %s failed: %v (attempt %d/%d, %s)", method, path, err, attempt, attempts, elapsed)
+			return fmt.Errorf("making request: %w", err)
 		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
+		c.logf("%s %s -> %s (attempt %d/%d, %s)", method, path, resp.Status, attempt, attempts, elapsed)
 
-	var keyResponse KeyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&keyResponse); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+		return c.decodeResponse(resp, method, path, out)
 	}
-
-	return &keyResponse, nil
 }
 
-// ListTeamKeys gets all API keys for a team
-func (c *Client) ListTeamKeys(identifier string) ([]KeyResponse, error) {
-	teamID, err := c.resolveTeamIdentifier(identifier)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/key/list?page=1&size=100&team_id=%s", c.BaseURL, teamID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
-	}
+// decodeResponse reads resp and decodes it into out, or converts it into an
+// *APIError if the request failed
+func (c *Client) decodeResponse(resp *http.Response, method, path string, out interface{}) error {
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
-		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var listResponse KeyListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&listResponse); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Get detailed info for each key
-	var keys []KeyResponse
-	for _, keyID := range listResponse.Keys {
-		keyInfo, err := c.GetKeyInfo(keyID)
-		if err != nil {
-			return nil, fmt.Errorf("getting key info for %s: %w", keyID, err)
-		}
-		keys = append(keys, *keyInfo)
-	}
-
-	return keys, nil
-}
-
-// ListTeams gets all teams
-func (c *Client) ListTeams() ([]Team, error) {
-	url := fmt.Sprintf("%s/team/list", c.BaseURL)
-	req, err := http.NewRequest("GET", url, nil)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("reading response: %w", err)
 	}
 
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(method, path, resp.StatusCode, data)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
-		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
+	if out == nil {
+		return nil
 	}
-
-	var teams []Team
-	if err := json.NewDecoder(resp.Body).Decode(&teams); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
 
-	return teams, nil
+	return nil
 }
 
-// GetTeamInfo gets detailed information about a team by ID or alias
-func (c *Client) GetTeamInfo(identifier string) (*Team, error) {
-	teams, err := c.ListTeams()
-	if err != nil {
-		return nil, err
-	}
-
-	for _, team := range teams {
-		if team.TeamID == identifier || team.TeamAlias == identifier {
-			return &team, nil
-		}
+// logf writes a line to c.Verbose if verbose output is enabled
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.Verbose != nil {
+		fmt.Fprintf(c.Verbose, format+"\n", args...)
 	}
-
-	return nil, fmt.Errorf("team not found: %s", identifier)
 }
 
-// GetUserInfo gets detailed information about a user by ID or email
-func (c *Client) GetUserInfo(identifier string) (*UserResponse, error) {
-	var url string
-	if strings.Contains(identifier, "@") {
-		url = fmt.Sprintf("%s/user/info?email=%s", c.BaseURL, identifier)
-	} else {
-		url = fmt.Sprintf("%s/user/info?user_id=%s", c.BaseURL, identifier)
-	}
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
-		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var response UserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+// resolveTeamIdentifier gets team ID from either ID or alias
+func (c *Client) resolveTeamIdentifier(ctx context.Context, identifier string) (string, error) {
+	// If it looks like a UUID, use it directly
+	if len(identifier) == 36 && identifier[8] == '-' {
+		return identifier, nil
 	}
 
-	return &response, nil
-}
-
-// ListUsers gets all users
-func (c *Client) ListUsers() (*UserResponse, error) {
-	url := fmt.Sprintf("%s/user/info", c.BaseURL)
-	req, err := http.NewRequest("GET", url, nil)
+	// Otherwise, try to find the team by alias, or by a custom non-UUID ID
+	teams, err := c.ListTeamsContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("x-api-key", c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("decoding error response: %w", err)
+	for _, team := range teams {
+		if team.TeamAlias == identifier || team.TeamID == identifier {
+			return team.TeamID, nil
 		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Code, apiErr.Message)
-	}
-
-	var response UserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
-	return &response, nil
+	return "", fmt.Errorf("team not found: %s", identifier)
 }
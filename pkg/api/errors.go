@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for every non-2xx response from the API. Callers can
+// use errors.As to inspect the HTTP status and the server supplied details.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Code       string
+	Message    string
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" && e.Code != fmt.Sprint(e.StatusCode) {
+		message = fmt.Sprintf("%s - %s", e.Code, message)
+	}
+	return fmt.Sprintf("API error: %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, message)
+}
+
+// newAPIError builds an APIError from a failed response, extracting the error
+// code and message from whichever error format the proxy used.
+func newAPIError(method, path string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       body,
+	}
+	apiErr.Code, apiErr.Message = parseErrorBody(body)
+	return apiErr
+}
+
+// parseErrorBody understands the flat {"code", "message"} format, LiteLLM's
+// {"error": {...}} envelope and FastAPI's {"detail": ...} responses. Bodies
+// that are not JSON are used verbatim as the message.
+func parseErrorBody(body []byte) (code, message string) {
+	var payload struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+		Detail  json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", strings.TrimSpace(string(body))
+	}
+
+	code, message = rawString(payload.Code), payload.Message
+
+	if len(payload.Error) > 0 {
+		var nested struct {
+			Code    json.RawMessage `json:"code"`
+			Message string          `json:"message"`
+		}
+		if err := json.Unmarshal(payload.Error, &nested); err == nil {
+			if code == "" {
+				code = rawString(nested.Code)
+			}
+			if message == "" {
+				message = nested.Message
+			}
+		} else if message == "" {
+			message = rawString(payload.Error)
+		}
+	}
+
+	if message == "" && len(payload.Detail) > 0 {
+		var nested struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(payload.Detail, &nested); err == nil && nested.Error != "" {
+			message = nested.Error
+		} else {
+			message = rawString(payload.Detail)
+		}
+	}
+
+	return code, message
+}
+
+// rawString renders a JSON value as plain text, unquoting strings
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// statusCode returns the HTTP status of an APIError in err's chain, or 0
+func statusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsUnauthorized reports whether err is a 401 response
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is a 403 response
+func IsForbidden(err error) bool {
+	return statusCode(err) == http.StatusForbidden
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsRateLimited reports whether err is a 429 response
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsServerError reports whether err is a 5xx response
+func IsServerError(err error) bool {
+	code := statusCode(err)
+	return code >= 500 && code <= 599
+}
@@ -0,0 +1,537 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPageSize is the page size used when a listing does not set one
+const DefaultPageSize = 100
+
+// DefaultConcurrency is the number of parallel /key/info requests used when a
+// listing has to fetch key details one by one
+const DefaultConcurrency = 4
+
+// KeyListOptions filters and paginates key listings
+type KeyListOptions struct {
+	TeamID   string
+	UserID   string
+	KeyAlias string
+	// Page is the 1-based page to fetch; PageSize defaults to DefaultPageSize
+	Page     int
+	PageSize int
+	// AllPages fetches every page from Page onwards instead of a single page
+	AllPages bool
+	// Concurrency bounds the parallel /key/info requests ListTeamKeys makes
+	// when the server does not return full key objects
+	Concurrency int
+}
+
+// withDefaults fills in the first page, the default page size and the
+// default concurrency
+func (opts KeyListOptions) withDefaults() KeyListOptions {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.PageSize < 1 {
+		opts.PageSize = DefaultPageSize
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	return opts
+}
+
+// query builds the /key/list query parameters for opts
+func (opts KeyListOptions) query(fullObjects bool) url.Values {
+	query := url.Values{
+		"page": {strconv.Itoa(opts.Page)},
+		"size": {strconv.Itoa(opts.PageSize)},
+	}
+	if opts.TeamID != "" {
+		query.Set("team_id", opts.TeamID)
+	}
+	if opts.UserID != "" {
+		query.Set("user_id", opts.UserID)
+	}
+	if opts.KeyAlias != "" {
+		query.Set("key_alias", opts.KeyAlias)
+	}
+	if fullObjects {
+		query.Set("return_full_object", "true")
+		query.Set("include_team_keys", "true")
+		query.Set("sort_order", "desc")
+	}
+	return query
+}
+
+// filter drops the keys that do not belong to opts.UserID. Older servers
+// ignore the user_id filter, and callers block, delete or transfer every key
+// they get back, so results are filtered client side too.
+func (opts KeyListOptions) filter(keys []KeyObject) []KeyObject {
+	if opts.UserID == "" {
+		return keys
+	}
+	var result []KeyObject
+	for _, key := range keys {
+		if key.UserID == opts.UserID {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// GetKeyInfo gets detailed information about a specific key
+func (c *Client) GetKeyInfo(keyID string) (*KeyResponse, error) {
+	return c.GetKeyInfoContext(context.Background(), keyID)
+}
+
+// GetKeyInfoContext is like GetKeyInfo but honors ctx cancellation
+func (c *Client) GetKeyInfoContext(ctx context.Context, keyID string) (*KeyResponse, error) {
+	var keyResponse KeyResponse
+	query := url.Values{"key": {keyID}}
+	if err := c.do(ctx, http.MethodGet, "/key/info", query, nil, &keyResponse); err != nil {
+		return nil, err
+	}
+
+	return &keyResponse, nil
+}
+
+// ListKeys gets API keys, including team keys, with their full details. A
+// single page is returned unless opts.AllPages is set. The totals are the
+// server's and may count keys the user filter removed.
+func (c *Client) ListKeys(opts KeyListOptions) (*KeyObjectListResponse, error) {
+	return c.ListKeysContext(context.Background(), opts)
+}
+
+// ListKeysContext is like ListKeys but honors ctx cancellation
+func (c *Client) ListKeysContext(ctx context.Context, opts KeyListOptions) (*KeyObjectListResponse, error) {
+	opts = opts.withDefaults()
+	if !opts.AllPages {
+		response, err := c.listKeysPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		response.Keys = opts.filter(response.Keys)
+		return response, nil
+	}
+
+	iter := c.KeysIter(ctx, opts)
+	response := &KeyObjectListResponse{}
+	for iter.Next() {
+		response.Keys = append(response.Keys, iter.Key())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	response.TotalCount = iter.TotalCount()
+	response.CurrentPage = iter.lastPage
+	response.TotalPages = iter.totalPages
+
+	return response, nil
+}
+
+// ListAllKeys gets every API key matching opts, walking all pages
+func (c *Client) ListAllKeys(opts KeyListOptions) ([]KeyObject, error) {
+	return c.ListAllKeysContext(context.Background(), opts)
+}
+
+// ListAllKeysContext is like ListAllKeys but honors ctx cancellation
+func (c *Client) ListAllKeysContext(ctx context.Context, opts KeyListOptions) ([]KeyObject, error) {
+	opts.AllPages = true
+	response, err := c.ListKeysContext(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Keys, nil
+}
+
+// listKeysPage fetches the single page of full key objects selected by opts
+func (c *Client) listKeysPage(ctx context.Context, opts KeyListOptions) (*KeyObjectListResponse, error) {
+	var response KeyObjectListResponse
+	if err := c.do(ctx, http.MethodGet, "/key/list", opts.query(true), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// KeyIterator walks every page of a key listing, fetching pages as needed.
+// Use it like bufio.Scanner:
+//
+//	iter := client.KeysIter(ctx, opts)
+//	for iter.Next() {
+//		key := iter.Key()
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type KeyIterator struct {
+	client     *Client
+	ctx        context.Context
+	opts       KeyListOptions
+	keys       []KeyObject
+	index      int
+	done       bool
+	err        error
+	total      int
+	lastPage   int
+	totalPages int
+}
+
+// KeysIter returns an iterator over every key matching opts, starting at
+// opts.Page
+func (c *Client) KeysIter(ctx context.Context, opts KeyListOptions) *KeyIterator {
+	return &KeyIterator{
+		client: c,
+		ctx:    ctx,
+		opts:   opts.withDefaults(),
+		index:  -1,
+	}
+}
+
+// Next advances to the next key, fetching the next page when the current one
+// is exhausted. It returns false at the end of the listing or on error.
+func (it *KeyIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	for it.index >= len(it.keys) {
+		if it.done {
+			return false
+		}
+
+		response, err := it.client.listKeysPage(it.ctx, it.opts)
+		if err != nil {
+			it.err = fmt.Errorf("listing keys page %d: %w", it.opts.Page, err)
+			return false
+		}
+
+		it.keys, it.index = it.opts.filter(response.Keys), 0
+		it.total = response.TotalCount
+		it.lastPage = it.opts.Page
+		it.totalPages = response.TotalPages
+		it.done = len(response.Keys) == 0 || it.opts.Page >= response.TotalPages
+		it.opts.Page++
+	}
+
+	return true
+}
+
+// Key returns the current key
+func (it *KeyIterator) Key() KeyObject {
+	return it.keys[it.index]
+}
+
+// Err returns the first error encountered while fetching pages
+func (it *KeyIterator) Err() error {
+	return it.err
+}
+
+// TotalCount returns the total number of keys reported by the server
+func (it *KeyIterator) TotalCount() int {
+	return it.total
+}
+
+// KeyError records a failure to fetch the details of a single key
+type KeyError struct {
+	Key string
+	Err error
+}
+
+// Error implements the error interface
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("getting key info for %s: %v", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// TeamKeysResult holds a team's keys along with the listing's page counts.
+// Keys whose details could not be fetched are left out of Keys and reported
+// in Warnings.
+type TeamKeysResult struct {
+	Keys       []KeyResponse
+	TotalCount int
+	TotalPages int
+	Warnings   []*KeyError
+}
+
+// ListTeamKeys gets the API keys for a team with their details. A single page
+// is returned unless opts.AllPages is set; opts.TeamID is ignored.
+//
+// Servers that support return_full_object include the details in the listing.
+// Older servers only return hashed tokens, in which case the details are
+// fetched with up to opts.Concurrency parallel /key/info requests.
+func (c *Client) ListTeamKeys(identifier string, opts KeyListOptions) (*TeamKeysResult, error) {
+	return c.ListTeamKeysContext(context.Background(), identifier, opts)
+}
+
+// ListTeamKeysContext is like ListTeamKeys but honors ctx cancellation. If ctx
+// is cancelled part way through, the keys fetched so far are returned together
+// with the error.
+func (c *Client) ListTeamKeysContext(ctx context.Context, identifier string, opts KeyListOptions) (*TeamKeysResult, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = opts.withDefaults()
+	opts.TeamID = teamID
+
+	result := &TeamKeysResult{}
+	for {
+		// The listing holds full objects or bare hashed tokens depending on
+		// whether the server understands return_full_object
+		var listResponse struct {
+			Keys       []json.RawMessage `json:"keys"`
+			TotalCount int               `json:"total_count"`
+			TotalPages int               `json:"total_pages"`
+		}
+		if err := c.do(ctx, http.MethodGet, "/key/list", opts.query(true), nil, &listResponse); err != nil {
+			if ctx.Err() != nil && len(result.Keys) > 0 {
+				return result, ctx.Err()
+			}
+			return nil, err
+		}
+		result.TotalCount = listResponse.TotalCount
+		result.TotalPages = listResponse.TotalPages
+
+		var tokens []string
+		for _, raw := range listResponse.Keys {
+			var key KeyObject
+			if err := json.Unmarshal(raw, &key); err == nil {
+				result.Keys = append(result.Keys, KeyResponse{Key: key.Token, Info: key.KeyInfo})
+				continue
+			}
+
+			var token string
+			if err := json.Unmarshal(raw, &token); err != nil {
+				return nil, fmt.Errorf("decoding response: unexpected key entry %s", raw)
+			}
+			tokens = append(tokens, token)
+		}
+
+		if len(tokens) > 0 {
+			keys, warnings := c.fetchKeyDetails(ctx, tokens, opts.Concurrency)
+			result.Keys = append(result.Keys, keys...)
+			result.Warnings = append(result.Warnings, warnings...)
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		if !opts.AllPages || len(listResponse.Keys) == 0 || opts.Page >= listResponse.TotalPages {
+			return result, nil
+		}
+		opts.Page++
+	}
+}
+
+// fetchKeyDetails calls /key/info for every token using a pool of concurrency
+// workers. Results keep the order of tokens; keys that fail are returned as
+// warnings instead. Work stops early if ctx is cancelled.
+func (c *Client) fetchKeyDetails(ctx context.Context, tokens []string, concurrency int) ([]KeyResponse, []*KeyError) {
+	responses := make([]*KeyResponse, len(tokens))
+	errs := make([]error, len(tokens))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(tokens); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				responses[index], errs[index] = c.GetKeyInfoContext(ctx, tokens[index])
+			}
+		}()
+	}
+
+feed:
+	for index := range tokens {
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var keys []KeyResponse
+	var warnings []*KeyError
+	for index, token := range tokens {
+		switch {
+		case responses[index] != nil:
+			keys = append(keys, *responses[index])
+		case errs[index] != nil && ctx.Err() == nil:
+			warnings = append(warnings, &KeyError{Key: token, Err: errs[index]})
+		}
+	}
+
+	return keys, warnings
+}
+
+// GenerateKey creates a new API key. request.TeamID may be a team ID or alias.
+func (c *Client) GenerateKey(request GenerateKeyRequest) (*GenerateKeyResponse, error) {
+	return c.GenerateKeyContext(context.Background(), request)
+}
+
+// GenerateKeyContext is like GenerateKey but honors ctx cancellation
+func (c *Client) GenerateKeyContext(ctx context.Context, request GenerateKeyRequest) (*GenerateKeyResponse, error) {
+	if request.TeamID != "" {
+		teamID, err := c.resolveTeamIdentifier(ctx, request.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		request.TeamID = teamID
+	}
+
+	var response GenerateKeyResponse
+	if err := c.do(ctx, http.MethodPost, "/key/generate", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// HashToken returns the hashed token the proxy stores for a secret key
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// isHashedToken reports whether s looks like a hashed token (64 hex digits)
+func isHashedToken(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// ResolveKey finds a key by secret (sk-...), hashed token or alias. Aliases
+// must match exactly one key.
+func (c *Client) ResolveKey(identifier string) (*KeyObject, error) {
+	return c.ResolveKeyContext(context.Background(), identifier)
+}
+
+// ResolveKeyContext is like ResolveKey but honors ctx cancellation
+func (c *Client) ResolveKeyContext(ctx context.Context, identifier string) (*KeyObject, error) {
+	if strings.HasPrefix(identifier, "sk-") || isHashedToken(identifier) {
+		token := identifier
+		if strings.HasPrefix(identifier, "sk-") {
+			token = HashToken(identifier)
+		}
+
+		response, err := c.GetKeyInfoContext(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return &KeyObject{Token: token, KeyInfo: response.Info}, nil
+	}
+
+	keys, err := c.ListAllKeysContext(ctx, KeyListOptions{KeyAlias: identifier})
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter client side as well, older servers ignore the key_alias filter
+	var matches []KeyObject
+	for _, key := range keys {
+		if key.KeyAlias == identifier {
+			matches = append(matches, key)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("key not found: %s", identifier)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("alias %s matches %d keys, use the token instead", identifier, len(matches))
+	}
+}
+
+// DeleteKeys deletes keys by secret or hashed token
+func (c *Client) DeleteKeys(tokens []string) (*DeleteKeysResponse, error) {
+	return c.DeleteKeysContext(context.Background(), tokens)
+}
+
+// DeleteKeysContext is like DeleteKeys but honors ctx cancellation
+func (c *Client) DeleteKeysContext(ctx context.Context, tokens []string) (*DeleteKeysResponse, error) {
+	var response DeleteKeysResponse
+	if err := c.do(ctx, http.MethodPost, "/key/delete", nil, DeleteKeysRequest{Keys: tokens}, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// BlockKey blocks a key so the proxy rejects requests made with it
+func (c *Client) BlockKey(token string) (*KeyInfo, error) {
+	return c.BlockKeyContext(context.Background(), token)
+}
+
+// BlockKeyContext is like BlockKey but honors ctx cancellation
+func (c *Client) BlockKeyContext(ctx context.Context, token string) (*KeyInfo, error) {
+	var response KeyInfo
+	if err := c.do(ctx, http.MethodPost, "/key/block", nil, BlockKeyRequest{Key: token}, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// UnblockKey unblocks a previously blocked key
+func (c *Client) UnblockKey(token string) (*KeyInfo, error) {
+	return c.UnblockKeyContext(context.Background(), token)
+}
+
+// UnblockKeyContext is like UnblockKey but honors ctx cancellation
+func (c *Client) UnblockKeyContext(ctx context.Context, token string) (*KeyInfo, error) {
+	var response KeyInfo
+	if err := c.do(ctx, http.MethodPost, "/key/unblock", nil, BlockKeyRequest{Key: token}, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// UpdateKey changes a key's settings. request.TeamID may be a team ID or
+// alias. The response holds the fields the server reports as updated.
+func (c *Client) UpdateKey(request UpdateKeyRequest) (*KeyInfo, error) {
+	return c.UpdateKeyContext(context.Background(), request)
+}
+
+// UpdateKeyContext is like UpdateKey but honors ctx cancellation
+func (c *Client) UpdateKeyContext(ctx context.Context, request UpdateKeyRequest) (*KeyInfo, error) {
+	if request.TeamID != nil && *request.TeamID != "" {
+		teamID, err := c.resolveTeamIdentifier(ctx, *request.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		request.TeamID = &teamID
+	}
+
+	var response KeyInfo
+	if err := c.do(ctx, http.MethodPost, "/key/update", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
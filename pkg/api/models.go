@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ListModels gets all models available through the proxy
+func (c *Client) ListModels() (*ModelListResponse, error) {
+	return c.ListModelsContext(context.Background())
+}
+
+// ListModelsContext is like ListModels but honors ctx cancellation
+func (c *Client) ListModelsContext(ctx context.Context) (*ModelListResponse, error) {
+	var response ModelListResponse
+	query := url.Values{
+		"return_wildcard_routes":      {"false"},
+		"include_model_access_groups": {"false"},
+	}
+	if err := c.do(ctx, http.MethodGet, "/models", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetModelInfo gets detailed information about every model deployment
+func (c *Client) GetModelInfo() (*ModelInfoResponse, error) {
+	return c.GetModelInfoContext(context.Background())
+}
+
+// GetModelInfoContext is like GetModelInfo but honors ctx cancellation
+func (c *Client) GetModelInfoContext(ctx context.Context) (*ModelInfoResponse, error) {
+	var response ModelInfoResponse
+	if err := c.do(ctx, http.MethodGet, "/model/info", nil, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetModelHealth runs a health check against every deployment of a model
+func (c *Client) GetModelHealth(model string) (*ModelHealthResponse, error) {
+	return c.GetModelHealthContext(context.Background(), model)
+}
+
+// GetModelHealthContext is like GetModelHealth but honors ctx cancellation
+func (c *Client) GetModelHealthContext(ctx context.Context, model string) (*ModelHealthResponse, error) {
+	var response ModelHealthResponse
+	query := url.Values{"model": {model}}
+	if err := c.do(ctx, http.MethodGet, "/health", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// CheckLiveness checks that the proxy is up. /health/liveliness needs no API
+// key, so it tells an unreachable proxy from a rejected key.
+func (c *Client) CheckLiveness() error {
+	return c.CheckLivenessContext(context.Background())
+}
+
+// CheckLivenessContext is like CheckLiveness but honors ctx cancellation
+func (c *Client) CheckLivenessContext(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health/liveliness", nil, nil, nil)
+}
+
+// AllProxyModels is the model entry granting access to every model on the
+// proxy
+const AllProxyModels = "all-proxy-models"
+
+// ModelAllowed reports whether model is covered by an allowed model list, as
+// used by teams and keys. An empty list or all-proxy-models allows every
+// model; entries ending in * match by prefix, e.g. openai/*.
+func ModelAllowed(model string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, entry := range allowed {
+		if entry == AllProxyModels || entry == model {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok && strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are
+// retried. Only idempotent GET requests are retried unless RetryMutations is
+// set.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; zero
+	// disables retries.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry. It doubles on each
+	// further retry up to MaxBackoff, with jitter applied. A server asking
+	// to wait longer than MaxBackoff gets its error returned right away.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryMutations also retries POST requests, which may apply a change
+	// twice if the first response was lost.
+	RetryMutations bool
+}
+
+// DefaultRetryPolicy returns the policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// retryableStatus lists the responses that usually indicate a temporary
+// problem with the proxy or the load balancer in front of it
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// allows reports whether a request with the given method may be retried
+func (p RetryPolicy) allows(method string) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	return method == http.MethodGet || method == http.MethodHead || p.RetryMutations
+}
+
+// shouldRetry reports whether a request failed in a way worth retrying. Either
+// resp or err is set, as returned by http.Client.Do.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return retryableStatus[resp.StatusCode]
+}
+
+// backoff returns the delay before the given retry (starting at 1): the
+// exponential backoff with equal jitter, or the server's requested delay if
+// the response carries one. It returns false if the server asks to wait
+// longer than MaxBackoff, in which case the request should not be retried.
+func (p RetryPolicy) backoff(retry int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if delay, ok := serverDelay(resp.Header); ok {
+			return delay, p.MaxBackoff <= 0 || delay <= p.MaxBackoff
+		}
+	}
+
+	delay := p.InitialBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0, true
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1), true
+}
+
+// serverDelay reads the delay requested by the server from the Retry-After
+// header or, failing that, the largest of the x-ratelimit-reset-* headers
+// LiteLLM forwards from upstream providers.
+func serverDelay(header http.Header) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			delay := time.Until(date)
+			if delay < 0 {
+				delay = 0
+			}
+			return delay, true
+		}
+	}
+
+	var delay time.Duration
+	found := false
+	for _, name := range []string{"x-ratelimit-reset", "x-ratelimit-reset-requests", "x-ratelimit-reset-tokens"} {
+		if d, ok := parseResetHeader(header.Get(name)); ok {
+			found = true
+			if d > delay {
+				delay = d
+			}
+		}
+	}
+	return delay, found
+}
+
+// parseResetHeader parses rate limit reset values, which are either a number
+// of seconds ("12", "0.5") or a Go style duration ("1s", "6m0s", "20ms")
+func parseResetHeader(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RotatedAtMetadataKey is the metadata entry recording when a key was last
+// rotated. The proxy keeps created_at when a key is regenerated in place, so
+// this is what age based rotation compares against.
+const RotatedAtMetadataKey = "rotated_at"
+
+// Rotation methods reported in RotateKeyResult
+const (
+	RotateMethodRegenerate = "regenerate"
+	RotateMethodGenerate   = "generate"
+)
+
+// RegenerateKeyRequest represents the request body for regenerating a key
+type RegenerateKeyRequest struct {
+	Key      string                 `json:"key"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// RotateKeyOptions controls how a key is rotated
+type RotateKeyOptions struct {
+	// GracePeriod keeps the old key working for this long instead of
+	// invalidating it immediately. It is sent as the old key's new duration,
+	// e.g. "24h" or "7d".
+	GracePeriod string
+	// Now is the rotation time recorded in the key's metadata; the current
+	// time when zero
+	Now time.Time
+}
+
+// RotateKeyResult describes a completed rotation. Secret is the new key,
+// which the server never returns again.
+type RotateKeyResult struct {
+	Method        string `json:"method"`
+	OldToken      string `json:"old_token"`
+	NewToken      string `json:"new_token"`
+	KeyAlias      string `json:"key_alias,omitempty"`
+	TeamID        string `json:"team_id,omitempty"`
+	Secret        string `json:"secret"`
+	Expires       string `json:"expires,omitempty"`
+	OldKeyAlias   string `json:"old_key_alias,omitempty"`
+	OldKeyExpires string `json:"old_key_expires,omitempty"`
+}
+
+// RegenerateKey replaces a key's secret in place, keeping its settings
+func (c *Client) RegenerateKey(request RegenerateKeyRequest) (*GenerateKeyResponse, error) {
+	return c.RegenerateKeyContext(context.Background(), request)
+}
+
+// RegenerateKeyContext is like RegenerateKey but honors ctx cancellation
+func (c *Client) RegenerateKeyContext(ctx context.Context, request RegenerateKeyRequest) (*GenerateKeyResponse, error) {
+	var response GenerateKeyResponse
+	path := "/key/" + url.PathEscape(request.Key) + "/regenerate"
+	if err := c.do(ctx, http.MethodPost, path, nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RotateKey issues a new secret for key, preserving its alias, team, owner,
+// models, budgets, limits, expiry and metadata.
+//
+// Without a grace period the proxy's regenerate endpoint is used, so the old
+// secret stops working immediately. Servers without that endpoint, and
+// rotations with a grace period, generate a copy of the key instead; the old
+// key is then deleted, or renamed and set to expire after the grace period.
+func (c *Client) RotateKey(key KeyObject, opts RotateKeyOptions) (*RotateKeyResult, error) {
+	return c.RotateKeyContext(context.Background(), key, opts)
+}
+
+// RotateKeyContext is like RotateKey but honors ctx cancellation
+func (c *Client) RotateKeyContext(ctx context.Context, key KeyObject, opts RotateKeyOptions) (*RotateKeyResult, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	metadata := make(map[string]interface{}, len(key.Metadata)+1)
+	for k, v := range key.Metadata {
+		metadata[k] = v
+	}
+	metadata[RotatedAtMetadataKey] = opts.Now.UTC().Format(time.RFC3339)
+
+	if opts.GracePeriod == "" {
+		response, err := c.RegenerateKeyContext(ctx, RegenerateKeyRequest{Key: key.Token, Metadata: metadata})
+		if err == nil {
+			return &RotateKeyResult{
+				Method:   RotateMethodRegenerate,
+				OldToken: key.Token,
+				NewToken: response.HashedToken(),
+				KeyAlias: key.KeyAlias,
+				TeamID:   key.TeamID,
+				Secret:   response.Key,
+				Expires:  response.Expires,
+			}, nil
+		}
+		if !regenerateUnsupported(err) {
+			return nil, err
+		}
+	}
+
+	return c.rotateByGenerate(ctx, key, metadata, opts)
+}
+
+// regenerateUnsupported reports whether err means the server does not offer
+// /key/{key}/regenerate, which is missing on older proxies and limited to
+// enterprise licenses on others. Other 403s mean the caller lacks permission
+// and must not fall back to renaming, generating and deleting keys.
+func regenerateUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return true
+	case http.StatusForbidden:
+		return strings.Contains(strings.ToLower(apiErr.Message), "enterprise")
+	}
+	return false
+}
+
+// rotateByGenerate creates a copy of key and retires the old one. Aliases are
+// unique, so the old key gives up its alias first and gets it back if the new
+// key cannot be created.
+func (c *Client) rotateByGenerate(ctx context.Context, key KeyObject, metadata map[string]interface{}, opts RotateKeyOptions) (*RotateKeyResult, error) {
+	result := &RotateKeyResult{
+		Method:   RotateMethodGenerate,
+		OldToken: key.Token,
+		KeyAlias: key.KeyAlias,
+		TeamID:   key.TeamID,
+	}
+
+	if key.KeyAlias != "" {
+		result.OldKeyAlias = fmt.Sprintf("%s-rotated-%s", key.KeyAlias, opts.Now.UTC().Format("20060102150405"))
+		if _, err := c.UpdateKeyContext(ctx, UpdateKeyRequest{Key: key.Token, KeyAlias: &result.OldKeyAlias}); err != nil {
+			return nil, fmt.Errorf("renaming old key: %w", err)
+		}
+	}
+
+	request := GenerateKeyRequest{
+		KeyAlias:       key.KeyAlias,
+		TeamID:         key.TeamID,
+		UserID:         key.UserID,
+		Models:         key.Models,
+		MaxBudget:      key.MaxBudget,
+		BudgetDuration: key.BudgetDuration,
+		TPMLimit:       key.TPMLimit,
+		RPMLimit:       key.RPMLimit,
+		Metadata:       metadata,
+	}
+	if key.Expires != "" {
+		expires, err := parseTimestamp(key.Expires)
+		if err == nil {
+			remaining := expires.Sub(opts.Now)
+			if remaining < time.Second {
+				remaining = time.Second
+			}
+			request.Duration = fmt.Sprintf("%ds", int64(remaining/time.Second))
+		}
+	}
+
+	response, err := c.GenerateKeyContext(ctx, request)
+	if err != nil {
+		if key.KeyAlias != "" {
+			// Best effort: leave the old key as it was
+			c.UpdateKeyContext(context.WithoutCancel(ctx), UpdateKeyRequest{Key: key.Token, KeyAlias: &key.KeyAlias})
+		}
+		return nil, fmt.Errorf("generating new key: %w", err)
+	}
+	result.NewToken = response.HashedToken()
+	result.Secret = response.Key
+	result.Expires = response.Expires
+
+	if opts.GracePeriod != "" {
+		old, err := c.UpdateKeyContext(ctx, UpdateKeyRequest{Key: key.Token, Duration: &opts.GracePeriod})
+		if err != nil {
+			return result, fmt.Errorf("new key created, but setting the old key's grace period failed: %w", err)
+		}
+		result.OldKeyExpires = old.Expires
+		return result, nil
+	}
+
+	if _, err := c.DeleteKeysContext(ctx, []string{key.Token}); err != nil {
+		return result, fmt.Errorf("new key created, but deleting the old key failed: %w", err)
+	}
+	return result, nil
+}
+
+// LastRotated returns when key was last rotated according to its metadata,
+// falling back to its creation time
+func LastRotated(key KeyInfo) (time.Time, error) {
+	if value, ok := key.Metadata[RotatedAtMetadataKey].(string); ok {
+		if t, err := parseTimestamp(value); err == nil {
+			return t, nil
+		}
+	}
+	t, err := parseTimestamp(key.CreatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("key %s has no usable creation time: %q", key.KeyAlias, key.CreatedAt)
+	}
+	return t, nil
+}
+
+// parseTimestamp parses the timestamps returned by the proxy, which omit the
+// time zone when the database column has none
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05.999999999", value)
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListTeamMembers gets all members in a team
+func (c *Client) ListTeamMembers(identifier string) ([]TeamMember, error) {
+	return c.ListTeamMembersContext(context.Background(), identifier)
+}
+
+// ListTeamMembersContext is like ListTeamMembers but honors ctx cancellation
+func (c *Client) ListTeamMembersContext(ctx context.Context, identifier string) ([]TeamMember, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TeamResponse
+	query := url.Values{"team_id": {teamID}}
+	if err := c.do(ctx, http.MethodGet, "/team/info", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.TeamInfo.MembersWithRoles, nil
+}
+
+// ListTeamMemberDetails gets all members in a team with their in-team spend
+// and budget
+func (c *Client) ListTeamMemberDetails(identifier string) ([]TeamMemberDetails, error) {
+	return c.ListTeamMemberDetailsContext(context.Background(), identifier)
+}
+
+// ListTeamMemberDetailsContext is like ListTeamMemberDetails but honors ctx
+// cancellation
+func (c *Client) ListTeamMemberDetailsContext(ctx context.Context, identifier string) ([]TeamMemberDetails, error) {
+	team, err := c.GetTeamInfoContext(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return team.Members, nil
+}
+
+// UpdateTeamMember changes a member's role or in-team budget.
+// request.TeamID may be a team ID or alias.
+func (c *Client) UpdateTeamMember(request UpdateTeamMemberRequest) (*UpdateTeamMemberResponse, error) {
+	return c.UpdateTeamMemberContext(context.Background(), request)
+}
+
+// UpdateTeamMemberContext is like UpdateTeamMember but honors ctx cancellation
+func (c *Client) UpdateTeamMemberContext(ctx context.Context, request UpdateTeamMemberRequest) (*UpdateTeamMemberResponse, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, request.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	request.TeamID = teamID
+
+	var response UpdateTeamMemberResponse
+	if err := c.do(ctx, http.MethodPost, "/team/member_update", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// AddTeamMember adds a new member to a team
+func (c *Client) AddTeamMember(identifier string, member TeamMember) (*TeamResponse, error) {
+	return c.AddTeamMemberContext(context.Background(), identifier, member)
+}
+
+// AddTeamMemberContext is like AddTeamMember but honors ctx cancellation
+func (c *Client) AddTeamMemberContext(ctx context.Context, identifier string, member TeamMember) (*TeamResponse, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	request := AddMemberRequest{
+		Member: []TeamMember{member},
+		TeamID: teamID,
+	}
+
+	var response TeamResponse
+	if err := c.do(ctx, http.MethodPost, "/team/member_add", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RemoveTeamMember removes a member from a team
+func (c *Client) RemoveTeamMember(identifier string, member TeamMember) (*TeamResponse, error) {
+	return c.RemoveTeamMemberContext(context.Background(), identifier, member)
+}
+
+// RemoveTeamMemberContext is like RemoveTeamMember but honors ctx cancellation
+func (c *Client) RemoveTeamMemberContext(ctx context.Context, identifier string, member TeamMember) (*TeamResponse, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	request := RemoveMemberRequest{
+		UserID:    member.UserID,
+		UserEmail: member.UserEmail,
+		TeamID:    teamID,
+	}
+
+	var response TeamResponse
+	if err := c.do(ctx, http.MethodPost, "/team/member_delete", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ListTeams gets all teams
+func (c *Client) ListTeams() ([]Team, error) {
+	return c.ListTeamsContext(context.Background())
+}
+
+// ListTeamsContext is like ListTeams but honors ctx cancellation
+func (c *Client) ListTeamsContext(ctx context.Context) ([]Team, error) {
+	var teams []Team
+	if err := c.do(ctx, http.MethodGet, "/team/list", nil, nil, &teams); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetTeamInfo gets detailed information about a team by ID or alias from
+// /team/info, including its members and keys
+func (c *Client) GetTeamInfo(identifier string) (*TeamDetails, error) {
+	return c.GetTeamInfoContext(context.Background(), identifier)
+}
+
+// GetTeamInfoContext is like GetTeamInfo but honors ctx cancellation
+func (c *Client) GetTeamInfoContext(ctx context.Context, identifier string) (*TeamDetails, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	query := url.Values{"team_id": {teamID}}
+	if err := c.do(ctx, http.MethodGet, "/team/info", query, nil, &raw); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		TeamInfo        json.RawMessage  `json:"team_info"`
+		Keys            []KeyObject      `json:"keys"`
+		TeamMemberships []TeamMembership `json:"team_memberships"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("decoding team info: %w", err)
+	}
+
+	details := &TeamDetails{Keys: response.Keys, Raw: raw}
+	var info TeamInfo
+	if len(response.TeamInfo) > 0 {
+		if err := json.Unmarshal(response.TeamInfo, &details.Team); err != nil {
+			return nil, fmt.Errorf("decoding team info: %w", err)
+		}
+		if err := json.Unmarshal(response.TeamInfo, &info); err != nil {
+			return nil, fmt.Errorf("decoding team info: %w", err)
+		}
+	}
+	if details.TeamID == "" {
+		details.TeamID = teamID
+	}
+
+	memberships := make(map[string]TeamMembership, len(response.TeamMemberships))
+	for _, membership := range response.TeamMemberships {
+		memberships[membership.UserID] = membership
+	}
+
+	details.Members = make([]TeamMemberDetails, len(info.MembersWithRoles))
+	for i, member := range info.MembersWithRoles {
+		details.Members[i].TeamMember = member
+		if membership, ok := memberships[member.UserID]; ok {
+			details.Members[i].Spend = membership.Spend
+			if membership.BudgetTable != nil {
+				details.Members[i].MaxBudgetInTeam = membership.BudgetTable.MaxBudget
+			}
+		}
+	}
+
+	return details, nil
+}
+
+// CreateTeam creates a new team
+func (c *Client) CreateTeam(request CreateTeamRequest) (*Team, error) {
+	return c.CreateTeamContext(context.Background(), request)
+}
+
+// CreateTeamContext is like CreateTeam but honors ctx cancellation
+func (c *Client) CreateTeamContext(ctx context.Context, request CreateTeamRequest) (*Team, error) {
+	var team Team
+	if err := c.do(ctx, http.MethodPost, "/team/new", nil, request, &team); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// UpdateTeam changes a team's settings. request.TeamID may be a team ID or
+// alias.
+func (c *Client) UpdateTeam(request UpdateTeamRequest) (*Team, error) {
+	return c.UpdateTeamContext(context.Background(), request)
+}
+
+// UpdateTeamContext is like UpdateTeam but honors ctx cancellation
+func (c *Client) UpdateTeamContext(ctx context.Context, request UpdateTeamRequest) (*Team, error) {
+	teamID, err := c.resolveTeamIdentifier(ctx, request.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	request.TeamID = teamID
+
+	var response UpdateTeamResponse
+	if err := c.do(ctx, http.MethodPost, "/team/update", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
+// DeleteTeam deletes a team by ID or alias. The team's keys are deleted with
+// it.
+func (c *Client) DeleteTeam(identifier string) error {
+	return c.DeleteTeamContext(context.Background(), identifier)
+}
+
+// DeleteTeamContext is like DeleteTeam but honors ctx cancellation
+func (c *Client) DeleteTeamContext(ctx context.Context, identifier string) error {
+	teamID, err := c.resolveTeamIdentifier(ctx, identifier)
+	if err != nil {
+		return err
+	}
+
+	request := DeleteTeamsRequest{TeamIDs: []string{teamID}}
+	return c.do(ctx, http.MethodPost, "/team/delete", nil, request, nil)
+}
@@ -1,5 +1,7 @@
 package api
 
+import "encoding/json"
+
 // TeamMember represents a member of a team
 type TeamMember struct {
 	UserID    string `json:"user_id"`
@@ -32,23 +34,72 @@ type TeamInfo struct {
 
 // TeamResponse represents the API response for team operations
 type TeamResponse struct {
-	TeamID    string    `json:"team_id"`
-	TeamAlias string    `json:"team_alias"`
-	TeamInfo  TeamInfo  `json:"team_info"`
-	Keys      []KeyInfo `json:"keys"`
+	TeamID          string           `json:"team_id"`
+	TeamAlias       string           `json:"team_alias"`
+	TeamInfo        TeamInfo         `json:"team_info"`
+	Keys            []KeyInfo        `json:"keys"`
+	TeamMemberships []TeamMembership `json:"team_memberships"`
+}
+
+// TeamMembership represents a member's spend and budget within a team
+type TeamMembership struct {
+	UserID      string            `json:"user_id"`
+	TeamID      string            `json:"team_id"`
+	Spend       float64           `json:"spend"`
+	BudgetID    string            `json:"budget_id"`
+	BudgetTable *MembershipBudget `json:"litellm_budget_table"`
+}
+
+// MembershipBudget represents the budget attached to a team membership
+type MembershipBudget struct {
+	MaxBudget *float64 `json:"max_budget"`
+}
+
+// TeamMemberDetails combines a member's role with their in-team spend and
+// budget
+type TeamMemberDetails struct {
+	TeamMember
+	Spend           float64  `json:"spend"`
+	MaxBudgetInTeam *float64 `json:"max_budget_in_team"`
+}
+
+// UpdateTeamMemberRequest represents the request body for changing a team
+// member's role or in-team budget. Nil fields are left unchanged.
+type UpdateTeamMemberRequest struct {
+	TeamID          string   `json:"team_id"`
+	UserID          string   `json:"user_id,omitempty"`
+	UserEmail       string   `json:"user_email,omitempty"`
+	Role            string   `json:"role,omitempty"`
+	MaxBudgetInTeam *float64 `json:"max_budget_in_team,omitempty"`
+}
+
+// UpdateTeamMemberResponse represents the API response for updating a team
+// member
+type UpdateTeamMemberResponse struct {
+	TeamID          string   `json:"team_id"`
+	UserID          string   `json:"user_id"`
+	UserEmail       string   `json:"user_email"`
+	MaxBudgetInTeam *float64 `json:"max_budget_in_team"`
 }
 
 // KeyInfo represents detailed information about an API key
 type KeyInfo struct {
-	KeyName   string                 `json:"key_name"`
-	KeyAlias  string                 `json:"key_alias"`
-	Spend     float64                `json:"spend"`
-	Models    []string               `json:"models"`
-	TeamID    string                 `json:"team_id"`
-	UserID    string                 `json:"user_id"`
-	Metadata  map[string]interface{} `json:"metadata"`
-	CreatedAt string                 `json:"created_at"`
-	UpdatedAt string                 `json:"updated_at"`
+	KeyName        string                 `json:"key_name"`
+	KeyAlias       string                 `json:"key_alias"`
+	Spend          float64                `json:"spend"`
+	MaxBudget      *float64               `json:"max_budget"`
+	BudgetDuration string                 `json:"budget_duration"`
+	BudgetResetAt  string                 `json:"budget_reset_at"`
+	TPMLimit       *int64                 `json:"tpm_limit"`
+	RPMLimit       *int64                 `json:"rpm_limit"`
+	Models         []string               `json:"models"`
+	TeamID         string                 `json:"team_id"`
+	UserID         string                 `json:"user_id"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Blocked        bool                   `json:"blocked"`
+	Expires        string                 `json:"expires"`
+	CreatedAt      string                 `json:"created_at"`
+	UpdatedAt      string                 `json:"updated_at"`
 }
 
 // KeyResponse represents the API response for a key info request
@@ -67,13 +118,80 @@ type KeyListResponse struct {
 
 // Team represents detailed team information
 type Team struct {
-	TeamID    string   `json:"team_id"`
-	TeamAlias string   `json:"team_alias"`
-	Spend     float64  `json:"spend"`
-	Models    []string `json:"models"`
-	CreatedAt string   `json:"created_at"`
+	TeamID         string                 `json:"team_id"`
+	TeamAlias      string                 `json:"team_alias"`
+	Spend          float64                `json:"spend"`
+	MaxBudget      *float64               `json:"max_budget"`
+	BudgetDuration string                 `json:"budget_duration"`
+	TPMLimit       *int64                 `json:"tpm_limit"`
+	RPMLimit       *int64                 `json:"rpm_limit"`
+	Models         []string               `json:"models"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Blocked        bool                   `json:"blocked"`
+	BudgetResetAt  string                 `json:"budget_reset_at,omitempty"`
+	CreatedAt      string                 `json:"created_at"`
+	UpdatedAt      string                 `json:"updated_at,omitempty"`
+}
+
+// TeamDetails represents a team as returned by /team/info, with its members
+// and keys. Raw holds the complete response for passthrough output.
+type TeamDetails struct {
+	Team
+	Members []TeamMemberDetails `json:"members"`
+	Keys    []KeyObject         `json:"keys"`
+	Raw     json.RawMessage     `json:"-"`
+}
+
+// CreateTeamRequest represents the request body for creating a team
+type CreateTeamRequest struct {
+	TeamAlias      string                 `json:"team_alias"`
+	TeamID         string                 `json:"team_id,omitempty"`
+	Models         []string               `json:"models,omitempty"`
+	MaxBudget      *float64               `json:"max_budget,omitempty"`
+	BudgetDuration string                 `json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `json:"rpm_limit,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Blocked        bool                   `json:"blocked,omitempty"`
 }
 
+// UpdateTeamRequest represents the request body for updating a team. Nil
+// fields are left unchanged; fields named in Unset are explicitly cleared.
+type UpdateTeamRequest struct {
+	TeamID         string                 `json:"team_id"`
+	TeamAlias      *string                `json:"team_alias,omitempty"`
+	Models         []string               `json:"models,omitempty"`
+	MaxBudget      *float64               `json:"max_budget,omitempty"`
+	BudgetDuration *string                `json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `json:"rpm_limit,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Blocked        *bool                  `json:"blocked,omitempty"`
+	// Unset lists JSON field names to send as null, e.g. "max_budget"
+	Unset []string `json:"-"`
+}
+
+// MarshalJSON sends an empty but non-nil Models or Metadata and adds explicit
+// nulls for Unset fields, like UpdateKeyRequest
+func (r UpdateTeamRequest) MarshalJSON() ([]byte, error) {
+	type plain UpdateTeamRequest
+	return marshalUpdate(plain(r), r.Models, r.Metadata, r.Unset)
+}
+
+// UpdateTeamResponse represents the API response for updating a team
+type UpdateTeamResponse struct {
+	TeamID string `json:"team_id"`
+	Data   Team   `json:"data"`
+}
+
+// DeleteTeamsRequest represents the request body for deleting teams
+type DeleteTeamsRequest struct {
+	TeamIDs []string `json:"team_ids"`
+}
+
+// UserRoles are the roles the proxy assigns to users
+var UserRoles = []string{"proxy_admin", "proxy_admin_viewer", "internal_user", "internal_user_viewer"}
+
 // UserInfo represents detailed user information
 type UserInfo struct {
 	UserID    string                 `json:"user_id"`
@@ -89,6 +207,55 @@ type UserInfo struct {
 	UpdatedAt string                 `json:"updated_at"`
 }
 
+// CreateUserRequest represents the request body for creating a user
+type CreateUserRequest struct {
+	UserID    string                 `json:"user_id,omitempty"`
+	UserEmail string                 `json:"user_email,omitempty"`
+	UserAlias string                 `json:"user_alias,omitempty"`
+	UserRole  string                 `json:"user_role,omitempty"`
+	Models    []string               `json:"models,omitempty"`
+	MaxBudget *float64               `json:"max_budget,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// AutoCreateKey asks the server to also generate a key for the user; the
+	// server's default is to do so, so it is always sent
+	AutoCreateKey bool `json:"auto_create_key"`
+}
+
+// CreateUserResponse represents the API response for a created user. Key is
+// only set when a key was created with the user.
+type CreateUserResponse struct {
+	UserID    string `json:"user_id"`
+	UserEmail string `json:"user_email"`
+	UserRole  string `json:"user_role"`
+	Key       string `json:"key"`
+}
+
+// UpdateUserRequest represents the request body for updating a user. Nil
+// fields are left unchanged; fields named in Unset are explicitly cleared.
+type UpdateUserRequest struct {
+	UserID    string                 `json:"user_id"`
+	UserEmail *string                `json:"user_email,omitempty"`
+	UserAlias *string                `json:"user_alias,omitempty"`
+	UserRole  *string                `json:"user_role,omitempty"`
+	MaxBudget *float64               `json:"max_budget,omitempty"`
+	Models    []string               `json:"models,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// Unset lists JSON field names to send as null, e.g. "max_budget"
+	Unset []string `json:"-"`
+}
+
+// MarshalJSON sends an empty but non-nil Models or Metadata and adds explicit
+// nulls for Unset fields, like UpdateKeyRequest
+func (r UpdateUserRequest) MarshalJSON() ([]byte, error) {
+	type plain UpdateUserRequest
+	return marshalUpdate(plain(r), r.Models, r.Metadata, r.Unset)
+}
+
+// DeleteUsersRequest represents the request body for deleting users
+type DeleteUsersRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
 // UserResponse represents the API response for user operations
 type UserResponse struct {
 	UserID   string     `json:"user_id"`
@@ -97,8 +264,209 @@ type UserResponse struct {
 	Teams    []TeamInfo `json:"teams"`
 }
 
-// Error represents an API error response
+// Error represents an API error response.
+//
+// Deprecated: the client no longer returns Error. Failed requests return an
+// *APIError, which also carries the status, method, path and raw body; use
+// errors.As to inspect it.
 type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// KeyObject represents a key returned by /key/list with full objects
+type KeyObject struct {
+	Token string `json:"token"`
+	KeyInfo
+}
+
+// KeyObjectListResponse represents the API response for listing keys with
+// return_full_object=true
+type KeyObjectListResponse struct {
+	Keys        []KeyObject `json:"keys"`
+	TotalCount  int         `json:"total_count"`
+	CurrentPage int         `json:"current_page"`
+	TotalPages  int         `json:"total_pages"`
+}
+
+// DeleteKeysRequest represents the request body for deleting keys
+type DeleteKeysRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// DeleteKeysResponse represents the API response for deleting keys
+type DeleteKeysResponse struct {
+	DeletedKeys []string `json:"deleted_keys"`
+}
+
+// BlockKeyRequest represents the request body for blocking or unblocking a key
+type BlockKeyRequest struct {
+	Key string `json:"key"`
+}
+
+// UpdateKeyRequest represents the request body for updating a key. Nil fields
+// are left unchanged; fields named in Unset are explicitly cleared.
+type UpdateKeyRequest struct {
+	Key            string                 `json:"key"`
+	KeyAlias       *string                `json:"key_alias,omitempty"`
+	TeamID         *string                `json:"team_id,omitempty"`
+	UserID         *string                `json:"user_id,omitempty"`
+	Models         []string               `json:"models,omitempty"`
+	MaxBudget      *float64               `json:"max_budget,omitempty"`
+	BudgetDuration *string                `json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `json:"rpm_limit,omitempty"`
+	Duration       *string                `json:"duration,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	// Unset lists JSON field names to send as null, e.g. "max_budget"
+	Unset []string `json:"-"`
+}
+
+// MarshalJSON sends an empty but non-nil Models or Metadata (e.g. removing
+// the last model) and adds explicit nulls for Unset fields
+func (r UpdateKeyRequest) MarshalJSON() ([]byte, error) {
+	type plain UpdateKeyRequest
+	return marshalUpdate(plain(r), r.Models, r.Metadata, r.Unset)
+}
+
+// marshalUpdate encodes the plain (method-less) form of an update request,
+// keeping empty but non-nil models and metadata and sending the unset fields
+// as null
+func marshalUpdate(plain interface{}, models []string, metadata map[string]interface{}, unset []string) ([]byte, error) {
+	data, err := json.Marshal(plain)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	if models != nil {
+		fields["models"] = models
+	}
+	if metadata != nil {
+		fields["metadata"] = metadata
+	}
+	for _, name := range unset {
+		fields[name] = nil
+	}
+	return json.Marshal(fields)
+}
+
+// Model represents a model as listed by the OpenAI compatible /models endpoint
+type Model struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// ModelListResponse represents the API response for listing models
+type ModelListResponse struct {
+	Data   []Model `json:"data"`
+	Object string  `json:"object"`
+}
+
+// ModelParams represents the LiteLLM routing parameters of a model
+type ModelParams struct {
+	InputCostPerToken  float64 `json:"input_cost_per_token"`
+	OutputCostPerToken float64 `json:"output_cost_per_token"`
+	ApiBase            string  `json:"api_base"`
+	ApiVersion         string  `json:"api_version"`
+	CustomProvider     string  `json:"custom_llm_provider"`
+}
+
+// ModelDetails represents the capabilities and metadata of a model
+type ModelDetails struct {
+	ID                string `json:"id"`
+	BaseModel         string `json:"base_model"`
+	Tier              string `json:"tier"`
+	Mode              string `json:"mode"`
+	MaxTokens         int    `json:"max_tokens"`
+	LitellmProvider   string `json:"litellm_provider"`
+	SupportsVision    bool   `json:"supports_vision"`
+	SupportsFunction  bool   `json:"supports_function_calling"`
+	SupportsTool      bool   `json:"supports_tool_choice"`
+	SupportsStreaming bool   `json:"supports_native_streaming"`
+}
+
+// ModelInfo represents detailed information about a model deployment
+type ModelInfo struct {
+	ModelName     string       `json:"model_name"`
+	LitellmParams ModelParams  `json:"litellm_params"`
+	ModelInfo     ModelDetails `json:"model_info"`
+}
+
+// ModelInfoResponse represents the API response for model info
+type ModelInfoResponse struct {
+	Data []ModelInfo `json:"data"`
+}
+
+// HealthEndpoint represents a single deployment in a health check
+type HealthEndpoint struct {
+	ApiBase                   string `json:"api_base"`
+	ApiVersion                string `json:"api_version"`
+	CustomProvider            string `json:"custom_llm_provider"`
+	XMsRegion                 string `json:"x-ms-region"`
+	XRateLimitRemainingReqs   string `json:"x-ratelimit-remaining-requests"`
+	XRateLimitRemainingTokens string `json:"x-ratelimit-remaining-tokens"`
+}
+
+// ModelHealthResponse represents the API response for a model health check
+type ModelHealthResponse struct {
+	HealthyEndpoints   []HealthEndpoint `json:"healthy_endpoints"`
+	UnhealthyEndpoints []HealthEndpoint `json:"unhealthy_endpoints"`
+	HealthyCount       int              `json:"healthy_count"`
+	UnhealthyCount     int              `json:"unhealthy_count"`
+}
+
+// UserListResponse represents the API response for listing users
+type UserListResponse struct {
+	Users      []UserInfo `json:"users"`
+	Total      int        `json:"total"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"page_size"`
+	TotalPages int        `json:"total_pages"`
+}
+
+// GenerateKeyRequest represents the request body for generating a key
+type GenerateKeyRequest struct {
+	KeyAlias       string                 `json:"key_alias,omitempty"`
+	TeamID         string                 `json:"team_id,omitempty"`
+	UserID         string                 `json:"user_id,omitempty"`
+	Models         []string               `json:"models,omitempty"`
+	MaxBudget      *float64               `json:"max_budget,omitempty"`
+	BudgetDuration string                 `json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `json:"rpm_limit,omitempty"`
+	Duration       string                 `json:"duration,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// GenerateKeyResponse represents the API response for a generated key. Key
+// holds the secret, which the server never returns again.
+type GenerateKeyResponse struct {
+	Key            string                 `json:"key"`
+	KeyName        string                 `json:"key_name"`
+	KeyAlias       string                 `json:"key_alias"`
+	Token          string                 `json:"token"`
+	TokenID        string                 `json:"token_id"`
+	TeamID         string                 `json:"team_id"`
+	UserID         string                 `json:"user_id"`
+	Models         []string               `json:"models"`
+	MaxBudget      *float64               `json:"max_budget"`
+	BudgetDuration string                 `json:"budget_duration"`
+	TPMLimit       *int64                 `json:"tpm_limit"`
+	RPMLimit       *int64                 `json:"rpm_limit"`
+	Expires        string                 `json:"expires"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
+
+// HashedToken returns the hashed token identifying the generated key
+func (r *GenerateKeyResponse) HashedToken() string {
+	if r.TokenID != "" {
+		return r.TokenID
+	}
+	return r.Token
+}
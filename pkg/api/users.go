@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrUserNotFound is returned when no user matches a lookup
+var ErrUserNotFound = errors.New("user not found")
+
+// UserListOptions filters and paginates user listings
+type UserListOptions struct {
+	// Role, TeamID and Email filter users by role, team membership (ID or
+	// alias) and a case-insensitive email substring
+	Role   string
+	TeamID string
+	Email  string
+	// Page is the 1-based page to fetch; PageSize defaults to DefaultPageSize
+	Page     int
+	PageSize int
+	// AllPages fetches every page from Page onwards instead of a single page
+	AllPages bool
+}
+
+// withDefaults fills in the first page and the default page size
+func (opts UserListOptions) withDefaults() UserListOptions {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.PageSize < 1 {
+		opts.PageSize = DefaultPageSize
+	}
+	return opts
+}
+
+// query builds the /user/list query parameters for opts
+func (opts UserListOptions) query() url.Values {
+	query := url.Values{
+		"page":      {strconv.Itoa(opts.Page)},
+		"page_size": {strconv.Itoa(opts.PageSize)},
+	}
+	if opts.Role != "" {
+		query.Set("role", opts.Role)
+	}
+	if opts.TeamID != "" {
+		query.Set("team", opts.TeamID)
+	}
+	if opts.Email != "" {
+		query.Set("user_email", opts.Email)
+	}
+	return query
+}
+
+// matches reports whether user passes the filters in opts. Older servers
+// ignore the filter parameters, so results are filtered client side too.
+func (opts UserListOptions) matches(user UserInfo) bool {
+	if opts.Role != "" && user.UserRole != opts.Role {
+		return false
+	}
+	if opts.Email != "" && !strings.Contains(strings.ToLower(user.UserEmail), strings.ToLower(opts.Email)) {
+		return false
+	}
+	if opts.TeamID != "" {
+		for _, team := range user.Teams {
+			if team == opts.TeamID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// GetUserInfo gets detailed information about a user by ID or email
+func (c *Client) GetUserInfo(identifier string) (*UserResponse, error) {
+	return c.GetUserInfoContext(context.Background(), identifier)
+}
+
+// GetUserInfoContext is like GetUserInfo but honors ctx cancellation
+func (c *Client) GetUserInfoContext(ctx context.Context, identifier string) (*UserResponse, error) {
+	query := url.Values{}
+	if strings.Contains(identifier, "@") {
+		query.Set("email", identifier)
+	} else {
+		query.Set("user_id", identifier)
+	}
+
+	var response UserResponse
+	if err := c.do(ctx, http.MethodGet, "/user/info", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ListUsers gets users from /user/list. A single page is returned unless
+// opts.AllPages is set. The totals are the server's and may count users a
+// filter removed on servers that do not filter themselves.
+func (c *Client) ListUsers(opts UserListOptions) (*UserListResponse, error) {
+	return c.ListUsersContext(context.Background(), opts)
+}
+
+// ListUsersContext is like ListUsers but honors ctx cancellation
+func (c *Client) ListUsersContext(ctx context.Context, opts UserListOptions) (*UserListResponse, error) {
+	opts = opts.withDefaults()
+	if opts.TeamID != "" {
+		teamID, err := c.resolveTeamIdentifier(ctx, opts.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		opts.TeamID = teamID
+	}
+
+	result := &UserListResponse{Users: []UserInfo{}}
+	for {
+		var response UserListResponse
+		if err := c.do(ctx, http.MethodGet, "/user/list", opts.query(), nil, &response); err != nil {
+			if opts.AllPages {
+				return nil, fmt.Errorf("listing users page %d: %w", opts.Page, err)
+			}
+			return nil, err
+		}
+
+		for _, user := range response.Users {
+			if opts.matches(user) {
+				result.Users = append(result.Users, user)
+			}
+		}
+		result.Total = response.Total
+		result.Page = response.Page
+		result.PageSize = response.PageSize
+		result.TotalPages = response.TotalPages
+
+		if !opts.AllPages || len(response.Users) == 0 || opts.Page >= response.TotalPages {
+			return result, nil
+		}
+		opts.Page++
+	}
+}
+
+// FindUserByEmail looks up a user by email address, ignoring case. It returns
+// an error wrapping ErrUserNotFound when no user has that email.
+func (c *Client) FindUserByEmail(email string) (*UserInfo, error) {
+	return c.FindUserByEmailContext(context.Background(), email)
+}
+
+// FindUserByEmailContext is like FindUserByEmail but honors ctx cancellation
+func (c *Client) FindUserByEmailContext(ctx context.Context, email string) (*UserInfo, error) {
+	response, err := c.GetUserInfoContext(ctx, email)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && response.UserInfo != nil && strings.EqualFold(response.UserInfo.UserEmail, email) {
+		return response.UserInfo, nil
+	}
+
+	// Not every server looks users up by email on /user/info, so fall back
+	// to searching the user list before concluding the user does not exist
+	users, err := c.ListUsersContext(ctx, UserListOptions{Email: email, AllPages: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users.Users {
+		if strings.EqualFold(user.UserEmail, email) {
+			return &user, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, email)
+}
+
+// CreateUser creates a new user. No key is created for the user unless
+// request.AutoCreateKey is set.
+func (c *Client) CreateUser(request CreateUserRequest) (*CreateUserResponse, error) {
+	return c.CreateUserContext(context.Background(), request)
+}
+
+// CreateUserContext is like CreateUser but honors ctx cancellation
+func (c *Client) CreateUserContext(ctx context.Context, request CreateUserRequest) (*CreateUserResponse, error) {
+	var response CreateUserResponse
+	if err := c.do(ctx, http.MethodPost, "/user/new", nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// DeleteUser deletes a user by ID. The user's keys are deleted with it.
+func (c *Client) DeleteUser(userID string) error {
+	return c.DeleteUserContext(context.Background(), userID)
+}
+
+// DeleteUserContext is like DeleteUser but honors ctx cancellation
+func (c *Client) DeleteUserContext(ctx context.Context, userID string) error {
+	request := DeleteUsersRequest{UserIDs: []string{userID}}
+	return c.do(ctx, http.MethodPost, "/user/delete", nil, request, nil)
+}
+
+// UpdateUser changes a user's settings
+func (c *Client) UpdateUser(request UpdateUserRequest) error {
+	return c.UpdateUserContext(context.Background(), request)
+}
+
+// UpdateUserContext is like UpdateUser but honors ctx cancellation
+func (c *Client) UpdateUserContext(ctx context.Context, request UpdateUserRequest) error {
+	return c.do(ctx, http.MethodPost, "/user/update", nil, request, nil)
+}
@@ -0,0 +1,84 @@
+// Package config handles the navigatorctl config file: named contexts for
+// several proxies and edits that keep the file's comments.
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Config keys of contexts
+const (
+	ContextsKey       = "contexts"
+	CurrentContextKey = "current-context"
+)
+
+// Context is a named set of settings, usually the API URL and key of one
+// proxy
+type Context struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Current bool   `json:"current"`
+}
+
+// Contexts lists the contexts defined in v sorted by name, marking the one
+// named current
+func Contexts(v *viper.Viper, current string) []Context {
+	var contexts []Context
+	for name := range v.GetStringMap(ContextsKey) {
+		settings, _ := Settings(v, name)
+		sub := viper.New()
+		sub.MergeConfigMap(settings)
+		contexts = append(contexts, Context{
+			Name:    name,
+			URL:     sub.GetString("api.url"),
+			Current: strings.EqualFold(name, current),
+		})
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+	return contexts
+}
+
+// Settings returns the settings of the named context. Names are not case
+// sensitive, like every other config key.
+func Settings(v *viper.Viper, name string) (map[string]interface{}, error) {
+	contexts := v.GetStringMap(ContextsKey)
+	value, ok := contexts[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(contexts))
+		for n := range contexts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("context %q not found: the config file defines no contexts", name)
+		}
+		return nil, fmt.Errorf("context %q not found; available: %s", name, strings.Join(names, ", "))
+	}
+
+	// A context without settings is written as "name:" and reads as nil
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	settings, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("context %q must be a mapping of settings", name)
+	}
+	return settings, nil
+}
+
+// UseContext merges the settings of the named context over the top-level
+// settings of the config file. Flags and environment variables still take
+// precedence over both.
+func UseContext(v *viper.Viper, name string) error {
+	settings, err := Settings(v, name)
+	if err != nil {
+		return err
+	}
+	return v.MergeConfigMap(settings)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetValue sets the value at the dotted path of keys in the YAML file at
+// path, creating the file and any missing mappings. Comments, the order of
+// keys and the quoting of replaced values are kept, though blank lines may be
+// dropped. New values are written plain, so YAML infers their type.
+func SetValue(path string, keys []string, value string) error {
+	root, mode, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	node := root.Content[0]
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", strings.Join(keys[:i], "."))
+		}
+		child := lookup(node, key)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			if i == len(keys)-1 {
+				child = &yaml.Node{Kind: yaml.ScalarNode}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		node = child
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s is not a single value", strings.Join(keys, "."))
+	}
+	node.Tag = ""
+	node.Value = value
+
+	return writeFile(path, root, mode)
+}
+
+// readFile parses the YAML file at path, or returns an empty document when it
+// does not exist yet
+func readFile(path string) (*yaml.Node, os.FileMode, error) {
+	empty := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The file holds API keys
+		return empty, 0600, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", path, err)
+	}
+	if root.Kind == 0 {
+		return empty, info.Mode().Perm(), nil
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, 0, fmt.Errorf("%s: the config file must be a mapping", path)
+	}
+	return &root, info.Mode().Perm(), nil
+}
+
+func writeFile(path string, root *yaml.Node, mode os.FileMode) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), mode)
+}
+
+// lookup returns the value of key in a mapping node. Keys match regardless of
+// case, as viper reads them.
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
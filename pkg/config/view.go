@@ -0,0 +1,74 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultFile is the commented config file written by config init
+//
+//go:embed default.yaml
+var DefaultFile []byte
+
+// Redacted replaces API keys when settings are displayed
+const Redacted = "<redacted>"
+
+// View returns the effective settings of v for display: API keys, including
+// those of contexts, are redacted and durations are written as in the config
+// file
+func View(v *viper.Viper) map[string]interface{} {
+	return display(v.AllSettings(), "", true).(map[string]interface{})
+}
+
+// Get returns the effective value of a dotted key in v, with durations written
+// as in the config file, and whether it is set
+func Get(v *viper.Viper, key string) (interface{}, bool) {
+	if !v.IsSet(key) {
+		return nil, false
+	}
+	return display(v.Get(key), "", false), true
+}
+
+// display formats durations in value, redacting API keys if redact is set.
+// parent is the key holding value.
+func display(value interface{}, parent string, redact bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			if redact && parent == "api" && key == "key" && child != nil && child != "" {
+				result[key] = Redacted
+				continue
+			}
+			result[key] = display(child, key, redact)
+		}
+		return result
+	case time.Duration:
+		return value.String()
+	default:
+		return value
+	}
+}
+
+// ValidateURL checks that raw is an absolute http or https URL, as api.url
+// must be
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%q must not have a query or fragment", raw)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package diff compares two values field by field for before/after displays
+// and plans.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change describes a field whose value differs between two objects. Old is
+// nil for added fields and New is nil for removed ones.
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Fields compares the JSON representations of before and after and returns
+// the changed fields sorted by name. Nested objects are compared by dotted
+// path, e.g. metadata.owner; lists are compared as a whole.
+func Fields(before, after interface{}) ([]Change, error) {
+	oldFields, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for field, oldValue := range oldFields {
+		newValue, ok := newFields[field]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	for field, newValue := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			changes = append(changes, Change{Field: field, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+// flatten converts v to a map of dotted field paths to JSON values. Null
+// fields are left out so that unset and null compare equal.
+func flatten(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding value: %w", err)
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("value is not an object: %w", err)
+	}
+
+	fields := make(map[string]interface{})
+	flattenInto(fields, "", object)
+	return fields, nil
+}
+
+func flattenInto(fields map[string]interface{}, prefix string, object map[string]interface{}) {
+	for key, value := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			flattenInto(fields, path, v)
+		default:
+			fields[path] = v
+		}
+	}
+}
+
+// Format renders a field value for display; missing values show as <unset>
+func Format(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// String renders a change as a single line with a +, - or ~ marker
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("+ %s: %s", c.Field, Format(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("- %s: %s", c.Field, Format(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Field, Format(c.Old), Format(c.New))
+	}
+}
@@ -0,0 +1,204 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+)
+
+// Apply makes the change a describes. Actions must be applied in plan order
+// so that users and teams exist before they are referenced. For created keys
+// it returns the new secret, which the server never shows again.
+func (a Action) Apply(ctx context.Context, client *api.Client) (string, error) {
+	switch a.Kind {
+	case KindUser:
+		return "", a.applyUser(ctx, client)
+	case KindTeam:
+		return "", a.applyTeam(ctx, client)
+	case KindTeamMembership:
+		return "", a.applyMembership(ctx, client)
+	case KindKey:
+		return a.applyKey(ctx, client)
+	default:
+		return "", fmt.Errorf("unknown kind %q", a.Kind)
+	}
+}
+
+func (a Action) applyUser(ctx context.Context, client *api.Client) error {
+	if a.Op == OpDelete {
+		return client.DeleteUserContext(ctx, a.ID)
+	}
+
+	spec, _ := a.desired.(UserSpec)
+	if a.Op == OpCreate {
+		request := api.CreateUserRequest{
+			UserAlias: spec.Alias,
+			UserRole:  spec.Role,
+			Models:    spec.Models,
+			MaxBudget: spec.MaxBudget,
+			Metadata:  spec.Metadata,
+		}
+		if strings.Contains(a.Name, "@") {
+			request.UserEmail = a.Name
+		} else {
+			request.UserID = a.Name
+		}
+		_, err := client.CreateUserContext(ctx, request)
+		return err
+	}
+
+	return client.UpdateUserContext(ctx, api.UpdateUserRequest{
+		UserID:    a.ID,
+		UserAlias: optional(spec.Alias),
+		UserRole:  optional(spec.Role),
+		MaxBudget: spec.MaxBudget,
+		Models:    spec.Models,
+		Metadata:  spec.Metadata,
+	})
+}
+
+func (a Action) applyTeam(ctx context.Context, client *api.Client) error {
+	if a.Op == OpDelete {
+		return client.DeleteTeamContext(ctx, a.ID)
+	}
+
+	spec, _ := a.desired.(TeamSpec)
+	if a.Op == OpCreate {
+		_, err := client.CreateTeamContext(ctx, api.CreateTeamRequest{
+			TeamAlias:      a.Name,
+			Models:         spec.Models,
+			MaxBudget:      spec.MaxBudget,
+			BudgetDuration: spec.BudgetDuration,
+			TPMLimit:       spec.TPMLimit,
+			RPMLimit:       spec.RPMLimit,
+			Metadata:       spec.Metadata,
+			Blocked:        spec.Blocked != nil && *spec.Blocked,
+		})
+		return err
+	}
+
+	_, err := client.UpdateTeamContext(ctx, api.UpdateTeamRequest{
+		TeamID:         a.ID,
+		Models:         spec.Models,
+		MaxBudget:      spec.MaxBudget,
+		BudgetDuration: optional(spec.BudgetDuration),
+		TPMLimit:       spec.TPMLimit,
+		RPMLimit:       spec.RPMLimit,
+		Metadata:       spec.Metadata,
+		Blocked:        spec.Blocked,
+	})
+	return err
+}
+
+func (a Action) applyMembership(ctx context.Context, client *api.Client) error {
+	member, _ := a.desired.(roster.Member)
+
+	// Users created earlier in the same apply only get an ID now
+	if member.UserID == "" {
+		userID, err := lookupUser(ctx, client, member.Email)
+		if err != nil {
+			return err
+		}
+		member.UserID = userID
+	}
+
+	var err error
+	switch a.Op {
+	case OpCreate:
+		// The team may have been created earlier in the same apply, so it is
+		// looked up by alias
+		_, err = client.AddTeamMemberContext(ctx, a.Team, api.TeamMember{
+			UserID:    member.UserID,
+			UserEmail: member.Email,
+			Role:      member.Role,
+		})
+	case OpUpdate:
+		_, err = client.UpdateTeamMemberContext(ctx, api.UpdateTeamMemberRequest{
+			TeamID:    a.ID,
+			UserID:    member.UserID,
+			UserEmail: member.Email,
+			Role:      member.Role,
+		})
+	case OpDelete:
+		_, err = client.RemoveTeamMemberContext(ctx, a.ID, api.TeamMember{UserID: member.UserID, UserEmail: member.Email})
+	}
+	return err
+}
+
+func (a Action) applyKey(ctx context.Context, client *api.Client) (string, error) {
+	if a.Op == OpDelete {
+		_, err := client.DeleteKeysContext(ctx, []string{a.ID})
+		return "", err
+	}
+
+	spec, _ := a.desired.(KeySpec)
+	userID := spec.User
+	if strings.Contains(spec.User, "@") {
+		var err error
+		if userID, err = lookupUser(ctx, client, spec.User); err != nil {
+			return "", err
+		}
+		if userID == "" {
+			return "", fmt.Errorf("%w: %s", api.ErrUserNotFound, spec.User)
+		}
+	}
+
+	if a.Op == OpCreate {
+		response, err := client.GenerateKeyContext(ctx, api.GenerateKeyRequest{
+			KeyAlias:       a.Name,
+			TeamID:         spec.Team,
+			UserID:         userID,
+			Models:         spec.Models,
+			MaxBudget:      spec.MaxBudget,
+			BudgetDuration: spec.BudgetDuration,
+			TPMLimit:       spec.TPMLimit,
+			RPMLimit:       spec.RPMLimit,
+			Duration:       spec.Duration,
+			Metadata:       spec.Metadata,
+		})
+		if err != nil {
+			return "", err
+		}
+		return response.Key, nil
+	}
+
+	_, err := client.UpdateKeyContext(ctx, api.UpdateKeyRequest{
+		Key:            a.ID,
+		TeamID:         optional(spec.Team),
+		UserID:         optional(userID),
+		Models:         spec.Models,
+		MaxBudget:      spec.MaxBudget,
+		BudgetDuration: optional(spec.BudgetDuration),
+		TPMLimit:       spec.TPMLimit,
+		RPMLimit:       spec.RPMLimit,
+		Metadata:       spec.Metadata,
+	})
+	return "", err
+}
+
+// lookupUser returns the ID of the user with email, or "" if there is none
+func lookupUser(ctx context.Context, client *api.Client, email string) (string, error) {
+	if email == "" {
+		return "", nil
+	}
+	user, err := client.FindUserByEmailContext(ctx, email)
+	if errors.Is(err, api.ErrUserNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.UserID, nil
+}
+
+// optional returns a pointer to s, or nil when s is empty
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
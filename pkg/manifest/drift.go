@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"github.com/ncecere/navigatorctl/pkg/diff"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+)
+
+// Drift statuses of an object
+const (
+	StatusInSync     = "in-sync"
+	StatusChanged    = "changed"
+	StatusMissing    = "missing"
+	StatusUnexpected = "unexpected"
+)
+
+// Check is the drift status of one object. Unexpected objects are managed
+// objects no manifest describes. For memberships each change names a member,
+// with a nil Old for missing members and a nil New for unexpected ones.
+type Check struct {
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	Status  string        `json:"status"`
+	Changes []diff.Change `json:"changes,omitempty"`
+}
+
+// Drifted reports whether the object differs from its manifest
+func (c Check) Drifted() bool {
+	return c.Status != StatusInSync
+}
+
+// DetectDrift compares every manifest in set with the live state and returns
+// a check per manifest, followed by the unexpected objects. Objects that only
+// lack the managed_by marker are in sync, so a fresh export shows no drift.
+func DetectDrift(set *Set, state *State) ([]Check, error) {
+	plan, err := NewPlan(set, state, PlanOptions{Prune: true})
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*Check)
+	var unexpected []Check
+	for _, action := range plan.Actions {
+		if action.Kind == KindTeamMembership {
+			check := found[KindTeamMembership+"/"+action.Team]
+			if check == nil {
+				check = &Check{Kind: KindTeamMembership, Name: action.Team, Status: StatusChanged}
+				found[KindTeamMembership+"/"+action.Team] = check
+			}
+			check.Changes = append(check.Changes, memberChange(action))
+			continue
+		}
+
+		changes := withoutManagedBy(action.Changes)
+		switch {
+		case action.Op == OpDelete:
+			unexpected = append(unexpected, Check{Kind: action.Kind, Name: action.Name, Status: StatusUnexpected})
+		case action.Op == OpCreate:
+			found[action.Kind+"/"+action.Name] = &Check{Kind: action.Kind, Name: action.Name, Status: StatusMissing, Changes: changes}
+		case len(changes) > 0:
+			found[action.Kind+"/"+action.Name] = &Check{Kind: action.Kind, Name: action.Name, Status: StatusChanged, Changes: changes}
+		}
+	}
+
+	var checks []Check
+	add := func(kind, name string) {
+		if check, ok := found[kind+"/"+name]; ok {
+			checks = append(checks, *check)
+			return
+		}
+		checks = append(checks, Check{Kind: kind, Name: name, Status: StatusInSync})
+	}
+	for _, user := range set.Users {
+		add(KindUser, user.Name)
+	}
+	for _, team := range set.Teams {
+		add(KindTeam, team.Name)
+	}
+	for _, membership := range set.Memberships {
+		add(KindTeamMembership, membership.Name)
+	}
+	for _, key := range set.Keys {
+		add(KindKey, key.Name)
+	}
+
+	return append(checks, unexpected...), nil
+}
+
+// memberChange describes a membership action as a change to one member's role
+func memberChange(action Action) diff.Change {
+	member, _ := action.desired.(roster.Member)
+	change := diff.Change{Field: "member " + member.String()}
+	for _, c := range action.Changes {
+		change.Old, change.New = c.Old, c.New
+	}
+	if action.Op == OpDelete {
+		change.Old = member.Role
+	}
+	return change
+}
+
+// withoutManagedBy drops the managed_by marker, which apply adds on its own
+func withoutManagedBy(changes []diff.Change) []diff.Change {
+	var result []diff.Change
+	for _, change := range changes {
+		if change.Field != "metadata."+ManagedByKey {
+			result = append(result, change)
+		}
+	}
+	return result
+}
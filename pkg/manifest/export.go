@@ -0,0 +1,313 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secret metadata values in exported manifests. Plans keep
+// the live value of redacted fields, so exports can be applied unchanged.
+const Redacted = "<redacted>"
+
+// secretMetadataKey matches metadata keys whose values are redacted on export
+var secretMetadataKey = regexp.MustCompile(`(?i)(secret|password|passwd|token|api_?key|credential|private)`)
+
+// File is a manifest file produced by Export; Name is relative to the export
+// directory
+type File struct {
+	Name      string
+	Documents []Document
+}
+
+// Bytes renders the file's documents as a YAML stream
+func (f File) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range f.Documents {
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Snapshot reads the complete live state for an export: every user, every
+// team with its members and keys from /team/info, and the keys outside any
+// team
+func Snapshot(ctx context.Context, client *api.Client) (*State, error) {
+	users, err := client.ListUsersContext(ctx, api.UserListOptions{AllPages: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	teams, err := client.ListTeamsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
+
+	state := &State{Users: users.Users, Members: make(map[string][]api.TeamMember)}
+	for _, team := range teams {
+		details, err := client.GetTeamInfoContext(ctx, team.TeamID)
+		if err != nil {
+			return nil, fmt.Errorf("getting team %s: %w", getOrDefault(team.TeamAlias, team.TeamID), err)
+		}
+		state.Teams = append(state.Teams, details.Team)
+
+		members := make([]api.TeamMember, len(details.Members))
+		for i, member := range details.Members {
+			members[i] = member.TeamMember
+		}
+		state.Members[team.TeamID] = members
+
+		for _, key := range details.Keys {
+			// /team/info may leave out the team ID on its keys
+			key.TeamID = team.TeamID
+			state.Keys = append(state.Keys, key)
+		}
+	}
+
+	keys, err := client.ListAllKeysContext(ctx, api.KeyListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing keys: %w", err)
+	}
+	for _, key := range keys {
+		if key.TeamID == "" {
+			state.Keys = append(state.Keys, key)
+		}
+	}
+
+	return state, nil
+}
+
+// Export describes state as manifests: one file per team under teams/ with
+// the team, its members and its keys, users.yaml with every user and keys.yaml
+// with the keys outside any team. Documents, lists and metadata are sorted so
+// that unchanged state exports identically. Keys without a unique alias
+// cannot be described by a manifest and are reported as warnings instead.
+func Export(state *State) ([]File, []string) {
+	var files []File
+	var warnings []string
+
+	// Keys are named by alias, so skip unaliased and ambiguous ones
+	aliases := make(map[string]int)
+	for _, key := range state.Keys {
+		aliases[key.KeyAlias]++
+	}
+	keysByTeam := make(map[string][]api.KeyObject)
+	for _, key := range state.Keys {
+		switch {
+		case key.KeyAlias == "":
+			warnings = append(warnings, fmt.Sprintf("skipped key %s: it has no alias", shortToken(key.Token)))
+		case aliases[key.KeyAlias] > 1:
+			warnings = append(warnings, fmt.Sprintf("skipped key %s: %d keys have the alias %s", shortToken(key.Token), aliases[key.KeyAlias], key.KeyAlias))
+		default:
+			keysByTeam[key.TeamID] = append(keysByTeam[key.TeamID], key)
+		}
+	}
+	sort.Strings(warnings)
+
+	users := make(map[string]api.UserInfo)
+	for _, user := range state.Users {
+		users[user.UserID] = user
+	}
+
+	teams := append([]api.Team{}, state.Teams...)
+	sort.Slice(teams, func(i, j int) bool {
+		return teamName(teams[i]) < teamName(teams[j])
+	})
+	fileNames := make(map[string]bool)
+	for _, team := range teams {
+		name := teamName(team)
+		file := File{Name: "teams/" + uniqueFileName(slug(name), team.TeamID, fileNames) + ".yaml"}
+
+		file.Documents = append(file.Documents, newDocument(KindTeam, name, exportTeam(team)))
+		file.Documents = append(file.Documents, newDocument(KindTeamMembership, name, exportMembers(state.Members[team.TeamID], users)))
+		for _, key := range sortKeys(keysByTeam[team.TeamID]) {
+			file.Documents = append(file.Documents, newDocument(KindKey, key.KeyAlias, exportKey(key, name)))
+		}
+		files = append(files, file)
+	}
+
+	userFile := File{Name: "users.yaml"}
+	for _, user := range sortUsers(state.Users) {
+		userFile.Documents = append(userFile.Documents, newDocument(KindUser, userName(user), exportUser(user)))
+	}
+	files = append(files, userFile)
+
+	// Keys of teams that no longer exist are kept with the unassigned ones
+	var other []api.KeyObject
+	for teamID, keys := range keysByTeam {
+		if teamID == "" || state.team(teamID) == nil {
+			other = append(other, keys...)
+		}
+	}
+	if len(other) > 0 {
+		keyFile := File{Name: "keys.yaml"}
+		for _, key := range sortKeys(other) {
+			keyFile.Documents = append(keyFile.Documents, newDocument(KindKey, key.KeyAlias, exportKey(key, "")))
+		}
+		files = append(files, keyFile)
+	}
+
+	return files, warnings
+}
+
+func newDocument(kind, name string, spec interface{}) Document {
+	return Document{APIVersion: APIVersion, Kind: kind, Metadata: Metadata{Name: name}, Spec: spec}
+}
+
+func exportUser(user api.UserInfo) UserSpec {
+	spec := UserSpec{
+		Alias:    user.UserAlias,
+		Role:     user.UserRole,
+		Models:   sortedModels(user.Models),
+		Metadata: redact(user.Metadata),
+	}
+	if user.MaxBudget > 0 {
+		maxBudget := user.MaxBudget
+		spec.MaxBudget = &maxBudget
+	}
+	return spec
+}
+
+func exportTeam(team api.Team) TeamSpec {
+	spec := TeamSpec{
+		Models:         sortedModels(team.Models),
+		MaxBudget:      team.MaxBudget,
+		BudgetDuration: team.BudgetDuration,
+		TPMLimit:       team.TPMLimit,
+		RPMLimit:       team.RPMLimit,
+		Metadata:       redact(team.Metadata),
+	}
+	if team.Blocked {
+		spec.Blocked = &team.Blocked
+	}
+	return spec
+}
+
+// exportMembers lists members sorted by user ID, filling in emails from the
+// user listing when the team has none
+func exportMembers(members []api.TeamMember, users map[string]api.UserInfo) TeamMembershipSpec {
+	spec := TeamMembershipSpec{Members: []roster.Member{}}
+	for _, member := range members {
+		email := member.UserEmail
+		if email == "" {
+			email = users[member.UserID].UserEmail
+		}
+		spec.Members = append(spec.Members, roster.Member{
+			UserID: member.UserID,
+			Email:  email,
+			Role:   getOrDefault(member.Role, roster.DefaultRole),
+		})
+	}
+	sort.Slice(spec.Members, func(i, j int) bool {
+		a, b := spec.Members[i], spec.Members[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		return strings.ToLower(a.Email) < strings.ToLower(b.Email)
+	})
+	return spec
+}
+
+func exportKey(key api.KeyObject, team string) KeySpec {
+	return KeySpec{
+		Team:           team,
+		User:           key.UserID,
+		Models:         sortedModels(key.Models),
+		MaxBudget:      key.MaxBudget,
+		BudgetDuration: key.BudgetDuration,
+		TPMLimit:       key.TPMLimit,
+		RPMLimit:       key.RPMLimit,
+		Metadata:       redact(key.Metadata),
+	}
+}
+
+// redact copies metadata, replacing the values of secret-looking keys
+func redact(metadata map[string]interface{}) map[string]interface{} {
+	if len(metadata) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if secretMetadataKey.MatchString(k) {
+			v = Redacted
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// sortedModels returns a sorted copy of models. An empty list, meaning all
+// models, is left out.
+func sortedModels(models []string) []string {
+	if len(models) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, models...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func sortKeys(keys []api.KeyObject) []api.KeyObject {
+	sorted := append([]api.KeyObject{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].KeyAlias < sorted[j].KeyAlias
+	})
+	return sorted
+}
+
+func sortUsers(users []api.UserInfo) []api.UserInfo {
+	sorted := append([]api.UserInfo{}, users...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(userName(sorted[i])) < strings.ToLower(userName(sorted[j]))
+	})
+	return sorted
+}
+
+// userName names a user in manifests by email, or by ID without one
+func userName(user api.UserInfo) string {
+	return getOrDefault(user.UserEmail, user.UserID)
+}
+
+// teamName names a team in manifests by alias, or by ID without one
+func teamName(team api.Team) string {
+	return getOrDefault(team.TeamAlias, team.TeamID)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug turns a team name into a file name
+func slug(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// uniqueFileName returns base, or base with the team ID appended when another
+// team already uses it
+func uniqueFileName(base, teamID string, used map[string]bool) string {
+	name := base
+	if name == "" || used[name] {
+		name = strings.Trim(base+"-"+slug(teamID), "-")
+	}
+	used[name] = true
+	return name
+}
+
+// shortToken abbreviates a hashed token for messages
+func shortToken(token string) string {
+	if len(token) > 12 {
+		return token[:12]
+	}
+	return token
+}
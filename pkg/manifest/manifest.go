@@ -0,0 +1,341 @@
+// Package manifest reads declarative YAML manifests describing users, teams,
+// team memberships and keys, and plans and applies the changes needed to make
+// the proxy match them.
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+	"gopkg.in/yaml.v3"
+)
+
+// APIVersion is the manifest format version this package reads
+const APIVersion = "navigatorctl/v1"
+
+// Manifest kinds
+const (
+	KindUser           = "User"
+	KindTeam           = "Team"
+	KindTeamMembership = "TeamMembership"
+	KindKey            = "Key"
+)
+
+// Metadata names the object a manifest describes
+type Metadata struct {
+	Name string `yaml:"name"`
+}
+
+// Document is a single manifest as written to a file
+type Document struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   Metadata    `yaml:"metadata"`
+	Spec       interface{} `yaml:"spec"`
+}
+
+// UserSpec is the desired state of a user. The user's name is their email or
+// user ID. Empty fields are left unmanaged.
+type UserSpec struct {
+	Alias     string                 `yaml:"alias,omitempty" json:"alias,omitempty"`
+	Role      string                 `yaml:"role,omitempty" json:"role,omitempty"`
+	Models    []string               `yaml:"models,omitempty" json:"models,omitempty"`
+	MaxBudget *float64               `yaml:"max_budget,omitempty" json:"max_budget,omitempty"`
+	Metadata  map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// TeamSpec is the desired state of a team. The team's name is its alias.
+// Empty fields are left unmanaged.
+type TeamSpec struct {
+	Models         []string               `yaml:"models,omitempty" json:"models,omitempty"`
+	MaxBudget      *float64               `yaml:"max_budget,omitempty" json:"max_budget,omitempty"`
+	BudgetDuration string                 `yaml:"budget_duration,omitempty" json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `yaml:"tpm_limit,omitempty" json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `yaml:"rpm_limit,omitempty" json:"rpm_limit,omitempty"`
+	Metadata       map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Blocked        *bool                  `yaml:"blocked,omitempty" json:"blocked,omitempty"`
+}
+
+// TeamMembershipSpec lists every member of the team named by the manifest
+type TeamMembershipSpec struct {
+	Members []roster.Member `yaml:"members"`
+}
+
+// KeySpec is the desired state of a key. The key's name is its alias; the
+// secret is never part of a manifest. Team is a team alias or ID and User an
+// email or user ID. Duration only applies when the key is created. Empty
+// fields are left unmanaged.
+type KeySpec struct {
+	Team           string                 `yaml:"team,omitempty" json:"team,omitempty"`
+	User           string                 `yaml:"user,omitempty" json:"user,omitempty"`
+	Models         []string               `yaml:"models,omitempty" json:"models,omitempty"`
+	MaxBudget      *float64               `yaml:"max_budget,omitempty" json:"max_budget,omitempty"`
+	BudgetDuration string                 `yaml:"budget_duration,omitempty" json:"budget_duration,omitempty"`
+	TPMLimit       *int64                 `yaml:"tpm_limit,omitempty" json:"tpm_limit,omitempty"`
+	RPMLimit       *int64                 `yaml:"rpm_limit,omitempty" json:"rpm_limit,omitempty"`
+	Metadata       map[string]interface{} `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Duration       string                 `yaml:"duration,omitempty" json:"-"`
+}
+
+// User is a User manifest. Source is the file and line it was read from.
+type User struct {
+	Name   string
+	Spec   UserSpec
+	Source string
+}
+
+// Team is a Team manifest
+type Team struct {
+	Name   string
+	Spec   TeamSpec
+	Source string
+}
+
+// TeamMembership is a TeamMembership manifest; Name is the team
+type TeamMembership struct {
+	Name   string
+	Spec   TeamMembershipSpec
+	Source string
+}
+
+// Key is a Key manifest
+type Key struct {
+	Name   string
+	Spec   KeySpec
+	Source string
+}
+
+// Set holds every manifest read from a set of files
+type Set struct {
+	Users       []User
+	Teams       []Team
+	Memberships []TeamMembership
+	Keys        []Key
+}
+
+// Load reads the manifests in paths. Directories are searched recursively
+// for .yaml and .yml files, which are read in lexical order.
+func Load(paths ...string) (*Set, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(file))
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no manifest files found")
+	}
+
+	set := &Set{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := set.parse(bytes.NewReader(data), file); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, set.validate()
+}
+
+// Parse reads the manifests in a YAML stream of one or more documents.
+// source names the stream in error messages.
+func Parse(r io.Reader, source string) (*Set, error) {
+	set := &Set{}
+	if err := set.parse(r, source); err != nil {
+		return nil, err
+	}
+	return set, set.validate()
+}
+
+// document is a manifest whose spec is decoded once its kind is known
+type document struct {
+	APIVersion string    `yaml:"apiVersion"`
+	Kind       string    `yaml:"kind"`
+	Metadata   Metadata  `yaml:"metadata"`
+	Spec       yaml.Node `yaml:"spec"`
+}
+
+func (s *Set) parse(r io.Reader, source string) error {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind == yaml.ScalarNode && node.Content[0].Tag == "!!null" {
+			// Empty document, e.g. a trailing ---
+			continue
+		}
+		at := fmt.Sprintf("%s:%d", source, node.Content[0].Line)
+
+		var doc document
+		if err := decodeStrict(&node, &doc); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+		if err := s.add(doc, at); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+	}
+}
+
+// add decodes doc's spec according to its kind and adds it to the set
+func (s *Set) add(doc document, source string) error {
+	if doc.APIVersion != APIVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %s", doc.APIVersion, APIVersion)
+	}
+	name := strings.TrimSpace(doc.Metadata.Name)
+	if name == "" {
+		return fmt.Errorf("%s has no metadata.name", doc.Kind)
+	}
+
+	switch doc.Kind {
+	case KindUser:
+		user := User{Name: name, Source: source}
+		if err := decodeSpec(&doc.Spec, &user.Spec); err != nil {
+			return err
+		}
+		s.Users = append(s.Users, user)
+	case KindTeam:
+		team := Team{Name: name, Source: source}
+		if err := decodeSpec(&doc.Spec, &team.Spec); err != nil {
+			return err
+		}
+		s.Teams = append(s.Teams, team)
+	case KindTeamMembership:
+		membership := TeamMembership{Name: name, Source: source}
+		if err := decodeSpec(&doc.Spec, &membership.Spec); err != nil {
+			return err
+		}
+		s.Memberships = append(s.Memberships, membership)
+	case KindKey:
+		key := Key{Name: name, Source: source}
+		if err := decodeSpec(&doc.Spec, &key.Spec); err != nil {
+			return err
+		}
+		s.Keys = append(s.Keys, key)
+	default:
+		return fmt.Errorf("unknown kind %q, expected %s, %s, %s or %s",
+			doc.Kind, KindUser, KindTeam, KindTeamMembership, KindKey)
+	}
+	return nil
+}
+
+// decodeSpec decodes a spec node, which may be missing
+func decodeSpec(node *yaml.Node, out interface{}) error {
+	if node.Kind == 0 {
+		return nil
+	}
+	if err := decodeStrict(node, out); err != nil {
+		return fmt.Errorf("spec: %w", err)
+	}
+	return nil
+}
+
+// decodeStrict decodes node into out, rejecting unknown fields, which
+// yaml.Node.Decode does not do on its own
+func decodeStrict(node *yaml.Node, out interface{}) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(out)
+}
+
+// validate rejects duplicate names and invalid roles
+func (s *Set) validate() error {
+	seen := make(map[string]string)
+	check := func(kind, name, source string) error {
+		key := kind + "/" + name
+		if kind == KindUser {
+			key = strings.ToLower(key)
+		}
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("%s: %s %s is already defined at %s", source, kind, name, first)
+		}
+		seen[key] = source
+		return nil
+	}
+
+	for _, user := range s.Users {
+		if err := check(KindUser, user.Name, user.Source); err != nil {
+			return err
+		}
+		if user.Spec.Role != "" && !validUserRole(user.Spec.Role) {
+			return fmt.Errorf("%s: user %s has role %q, expected one of %s",
+				user.Source, user.Name, user.Spec.Role, strings.Join(api.UserRoles, ", "))
+		}
+	}
+	for _, team := range s.Teams {
+		if err := check(KindTeam, team.Name, team.Source); err != nil {
+			return err
+		}
+	}
+	for i := range s.Memberships {
+		membership := &s.Memberships[i]
+		if err := check(KindTeamMembership, membership.Name, membership.Source); err != nil {
+			return err
+		}
+		// The roster shares the members slice, so default roles are filled in
+		members := roster.Roster{Teams: []roster.Team{{Team: membership.Name, Members: membership.Spec.Members}}}
+		if err := members.Normalize(); err != nil {
+			return fmt.Errorf("%s: %w", membership.Source, err)
+		}
+	}
+	for _, key := range s.Keys {
+		if err := check(KindKey, key.Name, key.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validUserRole(role string) bool {
+	for _, known := range api.UserRoles {
+		if role == known {
+			return true
+		}
+	}
+	return false
+}
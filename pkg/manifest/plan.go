@@ -0,0 +1,441 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/diff"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+)
+
+// Operations in a plan
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Action is a single planned change. ID is the live object's ID for updates
+// and deletions: the user ID, team ID or hashed key token, and the team ID
+// for memberships. Team is the team a membership belongs to.
+type Action struct {
+	Op      string        `json:"op"`
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	ID      string        `json:"id,omitempty"`
+	Team    string        `json:"team,omitempty"`
+	Changes []diff.Change `json:"changes,omitempty"`
+
+	// desired is the spec, or the roster.Member for memberships, to apply
+	desired interface{}
+}
+
+// String describes the action for a plan listing, e.g. "+ create Team CHAT"
+func (a Action) String() string {
+	marker := map[string]string{OpCreate: "+", OpUpdate: "~", OpDelete: "-"}[a.Op]
+	return fmt.Sprintf("%s %s %s %s", marker, a.Op, a.Kind, a.Name)
+}
+
+// PlanOptions controls how a plan is computed
+type PlanOptions struct {
+	// Prune includes deletions of objects apply manages that no manifest
+	// describes any more, and of members missing from a TeamMembership
+	Prune bool
+}
+
+// Plan is the ordered list of changes that make the proxy match a set of
+// manifests. Users come before teams, teams before memberships and
+// memberships before keys; deletions follow in the reverse order.
+type Plan struct {
+	Actions []Action `json:"actions"`
+	// Skipped holds the deletions left out because pruning was not requested
+	Skipped []Action `json:"skipped"`
+}
+
+// Count returns the number of actions with op
+func (p *Plan) Count(op string) int {
+	n := 0
+	for _, action := range p.Actions {
+		if action.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// NewPlan compares set with the live state and returns the changes to make
+func NewPlan(set *Set, state *State, opts PlanOptions) (*Plan, error) {
+	planner := &planner{set: set, state: state}
+
+	steps := []func() error{planner.users, planner.teams, planner.memberships, planner.keys}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deletions run in reverse dependency order
+	var deletes []Action
+	for _, kind := range []string{KindKey, KindTeamMembership, KindTeam, KindUser} {
+		for _, action := range planner.deletes {
+			if action.Kind == kind {
+				deletes = append(deletes, action)
+			}
+		}
+	}
+
+	plan := &Plan{Actions: planner.actions, Skipped: []Action{}}
+	if plan.Actions == nil {
+		plan.Actions = []Action{}
+	}
+	if opts.Prune {
+		plan.Actions = append(plan.Actions, deletes...)
+	} else if deletes != nil {
+		plan.Skipped = deletes
+	}
+	return plan, nil
+}
+
+// planner accumulates the actions for each kind in manifest order
+type planner struct {
+	set     *Set
+	state   *State
+	actions []Action
+	deletes []Action
+}
+
+// change adds a create or update action for an object, comparing the fields
+// desired sets with live, which is nil for objects that do not exist yet
+func (p *planner) change(kind, name string, live interface{}, desired interface{}, normalized interface{}, id string) error {
+	desiredFields, err := specFields(normalized)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", kind, name, err)
+	}
+
+	if live == nil {
+		changes, err := diff.Fields(map[string]interface{}{}, desiredFields)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", kind, name, err)
+		}
+		p.actions = append(p.actions, Action{Op: OpCreate, Kind: kind, Name: name, Changes: changes, desired: desired})
+		return nil
+	}
+
+	liveFields, err := specFields(live)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", kind, name, err)
+	}
+	// Only the fields a manifest sets are managed
+	current := make(map[string]interface{})
+	for field := range desiredFields {
+		if value, ok := liveFields[field]; ok {
+			current[field] = value
+		}
+	}
+
+	changes, err := diff.Fields(current, desiredFields)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", kind, name, err)
+	}
+	if len(changes) > 0 {
+		p.actions = append(p.actions, Action{Op: OpUpdate, Kind: kind, Name: name, ID: id, Changes: changes, desired: desired})
+	}
+	return nil
+}
+
+func (p *planner) users() error {
+	declared := make(map[string]bool)
+	for _, user := range p.set.Users {
+		live := p.state.user(user.Name)
+
+		spec := user.Spec
+		if live != nil {
+			declared[live.UserID] = true
+			spec.Metadata = withManagedBy(spec.Metadata, live.Metadata)
+			if err := p.change(KindUser, user.Name, liveUserSpec(live), spec, spec, live.UserID); err != nil {
+				return err
+			}
+			continue
+		}
+		spec.Metadata = withManagedBy(spec.Metadata, nil)
+		if err := p.change(KindUser, user.Name, nil, spec, spec, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range p.state.Users {
+		if !declared[user.UserID] && managed(user.Metadata) {
+			p.deletes = append(p.deletes, Action{Op: OpDelete, Kind: KindUser, Name: getOrDefault(user.UserEmail, user.UserID), ID: user.UserID})
+		}
+	}
+	return nil
+}
+
+func (p *planner) teams() error {
+	declared := make(map[string]bool)
+	for _, team := range p.set.Teams {
+		live := p.state.team(team.Name)
+
+		spec := team.Spec
+		if live != nil {
+			declared[live.TeamID] = true
+			spec.Metadata = withManagedBy(spec.Metadata, live.Metadata)
+			if err := p.change(KindTeam, team.Name, liveTeamSpec(live), spec, spec, live.TeamID); err != nil {
+				return err
+			}
+			continue
+		}
+		spec.Metadata = withManagedBy(spec.Metadata, nil)
+		if err := p.change(KindTeam, team.Name, nil, spec, spec, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, team := range p.state.Teams {
+		if !declared[team.TeamID] && managed(team.Metadata) {
+			p.deletes = append(p.deletes, Action{Op: OpDelete, Kind: KindTeam, Name: getOrDefault(team.TeamAlias, team.TeamID), ID: team.TeamID})
+		}
+	}
+	return nil
+}
+
+func (p *planner) memberships() error {
+	for _, membership := range p.set.Memberships {
+		var teamID string
+		var current []api.TeamMember
+		if team := p.state.team(membership.Name); team != nil {
+			teamID = team.TeamID
+			current = p.state.Members[team.TeamID]
+		} else if !p.set.hasTeam(membership.Name) {
+			return fmt.Errorf("%s: team %s does not exist and has no Team manifest", membership.Source, membership.Name)
+		}
+
+		// Match members given by email to existing users by ID, since team
+		// members often have no email on the server
+		desired := make([]roster.Member, len(membership.Spec.Members))
+		for i, member := range membership.Spec.Members {
+			if member.UserID == "" {
+				if user := p.state.user(member.Email); user != nil {
+					member.UserID = user.UserID
+				}
+			}
+			desired[i] = member
+		}
+
+		for _, change := range roster.Plan(membership.Name, desired, current, roster.PlanOptions{}) {
+			action := Action{
+				Kind:    KindTeamMembership,
+				Name:    membership.Name + "/" + change.Member.String(),
+				ID:      teamID,
+				Team:    membership.Name,
+				desired: change.Member,
+			}
+			switch change.Type {
+			case roster.ActionAdd:
+				action.Op = OpCreate
+				action.Changes = []diff.Change{{Field: "role", New: change.Member.Role}}
+				p.actions = append(p.actions, action)
+			case roster.ActionUpdateRole:
+				action.Op = OpUpdate
+				action.Changes = []diff.Change{{Field: "role", Old: change.OldRole, New: change.Member.Role}}
+				p.actions = append(p.actions, action)
+			case roster.ActionRemove:
+				action.Op = OpDelete
+				p.deletes = append(p.deletes, action)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *planner) keys() error {
+	declared := make(map[string]bool)
+	for _, key := range p.set.Keys {
+		spec := key.Spec
+		if spec.Team != "" && p.state.team(spec.Team) == nil && !p.set.hasTeam(spec.Team) {
+			return fmt.Errorf("%s: key %s: team %s does not exist and has no Team manifest", key.Source, key.Name, spec.Team)
+		}
+		if strings.Contains(spec.User, "@") && p.state.user(spec.User) == nil && !p.set.hasUser(spec.User) {
+			return fmt.Errorf("%s: key %s: user %s does not exist and has no User manifest", key.Source, key.Name, spec.User)
+		}
+
+		live := p.state.keys(key.Name)
+		if len(live) > 1 {
+			return fmt.Errorf("%s: %d keys have the alias %s; give them unique aliases first", key.Source, len(live), key.Name)
+		}
+
+		// Compare teams by alias and users by ID whichever way they are given
+		normalized := spec
+		if team := p.state.team(spec.Team); team != nil {
+			normalized.Team = getOrDefault(team.TeamAlias, team.TeamID)
+		}
+		if user := p.state.user(spec.User); user != nil {
+			normalized.User = user.UserID
+		}
+
+		if len(live) == 1 {
+			declared[live[0].Token] = true
+			spec.Metadata = withManagedBy(spec.Metadata, live[0].Metadata)
+			normalized.Metadata = spec.Metadata
+			if err := p.change(KindKey, key.Name, p.liveKeySpec(&live[0]), spec, normalized, live[0].Token); err != nil {
+				return err
+			}
+			continue
+		}
+		spec.Metadata = withManagedBy(spec.Metadata, nil)
+		normalized.Metadata = spec.Metadata
+		if err := p.change(KindKey, key.Name, nil, spec, normalized, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range p.state.Keys {
+		if !declared[key.Token] && managed(key.Metadata) {
+			p.deletes = append(p.deletes, Action{Op: OpDelete, Kind: KindKey, Name: getOrDefault(key.KeyAlias, key.Token), ID: key.Token})
+		}
+	}
+	return nil
+}
+
+// liveUserSpec describes a live user in manifest terms
+func liveUserSpec(user *api.UserInfo) UserSpec {
+	spec := UserSpec{
+		Alias:    user.UserAlias,
+		Role:     user.UserRole,
+		Models:   nonNil(user.Models),
+		Metadata: user.Metadata,
+	}
+	if user.MaxBudget > 0 {
+		maxBudget := user.MaxBudget
+		spec.MaxBudget = &maxBudget
+	}
+	return spec
+}
+
+// liveTeamSpec describes a live team in manifest terms
+func liveTeamSpec(team *api.Team) TeamSpec {
+	blocked := team.Blocked
+	return TeamSpec{
+		Models:         nonNil(team.Models),
+		MaxBudget:      team.MaxBudget,
+		BudgetDuration: team.BudgetDuration,
+		TPMLimit:       team.TPMLimit,
+		RPMLimit:       team.RPMLimit,
+		Metadata:       team.Metadata,
+		Blocked:        &blocked,
+	}
+}
+
+// liveKeySpec describes a live key in manifest terms, naming its team by
+// alias
+func (p *planner) liveKeySpec(key *api.KeyObject) KeySpec {
+	team := key.TeamID
+	if live := p.state.team(key.TeamID); live != nil {
+		team = getOrDefault(live.TeamAlias, live.TeamID)
+	}
+	return KeySpec{
+		Team:           team,
+		User:           key.UserID,
+		Models:         nonNil(key.Models),
+		MaxBudget:      key.MaxBudget,
+		BudgetDuration: key.BudgetDuration,
+		TPMLimit:       key.TPMLimit,
+		RPMLimit:       key.RPMLimit,
+		Metadata:       key.Metadata,
+	}
+}
+
+// specFields returns the JSON fields a spec sets. Models is kept when empty
+// but non-nil, since an empty list is a setting of its own, and sorted, since
+// its order does not matter.
+func specFields(spec interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	switch s := spec.(type) {
+	case UserSpec:
+		models = s.Models
+	case TeamSpec:
+		models = s.Models
+	case KeySpec:
+		models = s.Models
+	}
+	if models != nil {
+		sorted := append([]string{}, models...)
+		sort.Strings(sorted)
+		fields["models"] = sorted
+	}
+	return fields, nil
+}
+
+// withManagedBy returns the metadata to apply: the manifest's metadata, or
+// the live metadata when the manifest leaves it unmanaged, marked as managed.
+// Redacted values keep their live value.
+func withManagedBy(desired, live map[string]interface{}) map[string]interface{} {
+	source := desired
+	if source == nil {
+		source = live
+	}
+	metadata := make(map[string]interface{}, len(source)+1)
+	for k, v := range source {
+		if v == Redacted {
+			liveValue, ok := live[k]
+			if !ok {
+				continue
+			}
+			v = liveValue
+		}
+		metadata[k] = v
+	}
+	metadata[ManagedByKey] = ManagedByValue
+	return metadata
+}
+
+// hasTeam reports whether a Team manifest names team
+func (s *Set) hasTeam(team string) bool {
+	for _, t := range s.Teams {
+		if t.Name == team {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUser reports whether a User manifest names user
+func (s *Set) hasUser(user string) bool {
+	for _, u := range s.Users {
+		if u.Name == user || equalEmail(u.Name, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalEmail compares two email addresses, ignoring case
+func equalEmail(a, b string) bool {
+	return strings.Contains(a, "@") && strings.EqualFold(a, b)
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+func getOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
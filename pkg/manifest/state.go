@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+// Metadata marking the objects apply creates or updates, so that pruning
+// never deletes objects managed by hand
+const (
+	ManagedByKey   = "managed_by"
+	ManagedByValue = "navigatorctl"
+)
+
+// State is the live state of the proxy that manifests are compared with.
+// Members holds the members of the teams a TeamMembership manifest names,
+// keyed by team ID.
+type State struct {
+	Users   []api.UserInfo
+	Teams   []api.Team
+	Members map[string][]api.TeamMember
+	Keys    []api.KeyObject
+}
+
+// Fetch reads the live state needed to plan set
+func Fetch(ctx context.Context, client *api.Client, set *Set) (*State, error) {
+	users, err := client.ListUsersContext(ctx, api.UserListOptions{AllPages: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	teams, err := client.ListTeamsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing teams: %w", err)
+	}
+	keys, err := client.ListAllKeysContext(ctx, api.KeyListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing keys: %w", err)
+	}
+
+	state := &State{
+		Users:   users.Users,
+		Teams:   teams,
+		Members: make(map[string][]api.TeamMember),
+		Keys:    keys,
+	}
+	for _, membership := range set.Memberships {
+		team := state.team(membership.Name)
+		if team == nil {
+			continue
+		}
+		members, err := client.ListTeamMembersContext(ctx, team.TeamID)
+		if err != nil {
+			return nil, fmt.Errorf("listing members of team %s: %w", membership.Name, err)
+		}
+		state.Members[team.TeamID] = members
+	}
+
+	return state, nil
+}
+
+// team finds a team by alias or ID
+func (s *State) team(identifier string) *api.Team {
+	if identifier == "" {
+		return nil
+	}
+	for i := range s.Teams {
+		if s.Teams[i].TeamAlias == identifier || s.Teams[i].TeamID == identifier {
+			return &s.Teams[i]
+		}
+	}
+	return nil
+}
+
+// user finds a user by email, ignoring case, or by user ID
+func (s *State) user(identifier string) *api.UserInfo {
+	if identifier == "" {
+		return nil
+	}
+	for i := range s.Users {
+		if s.Users[i].UserID == identifier || equalEmail(s.Users[i].UserEmail, identifier) {
+			return &s.Users[i]
+		}
+	}
+	return nil
+}
+
+// keys finds the keys with alias
+func (s *State) keys(alias string) []api.KeyObject {
+	var keys []api.KeyObject
+	for _, key := range s.Keys {
+		if key.KeyAlias == alias {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// managed reports whether metadata marks an object as created by apply
+func managed(metadata map[string]interface{}) bool {
+	return metadata[ManagedByKey] == ManagedByValue
+}
@@ -0,0 +1,94 @@
+package roster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+// Action kinds in a plan
+const (
+	ActionAdd        = "add"
+	ActionRemove     = "remove"
+	ActionUpdateRole = "update-role"
+)
+
+// Action is a single membership change. OldRole is set for role changes.
+type Action struct {
+	Type    string `json:"type"`
+	Team    string `json:"team"`
+	Member  Member `json:"member"`
+	OldRole string `json:"old_role,omitempty"`
+}
+
+// String describes the action for a plan listing
+func (a Action) String() string {
+	switch a.Type {
+	case ActionAdd:
+		return fmt.Sprintf("+ add %s as %s", a.Member, a.Member.Role)
+	case ActionRemove:
+		return fmt.Sprintf("- remove %s (%s)", a.Member, a.Member.Role)
+	default:
+		return fmt.Sprintf("~ change %s from %s to %s", a.Member, a.OldRole, a.Member.Role)
+	}
+}
+
+// PlanOptions controls how a plan is computed
+type PlanOptions struct {
+	// NoRemove leaves members missing from the roster in the team
+	NoRemove bool
+}
+
+// Plan returns the actions that make current match desired for team.
+// Members are matched by user ID, or by email (ignoring case) when the
+// roster entry has no user ID. Adds come first, then role changes, then
+// removals, each in roster or team order.
+func Plan(team string, desired []Member, current []api.TeamMember, opts PlanOptions) []Action {
+	byID := make(map[string]int)
+	byEmail := make(map[string]int)
+	for i, member := range current {
+		if member.UserID != "" {
+			byID[member.UserID] = i
+		}
+		if member.UserEmail != "" {
+			byEmail[strings.ToLower(member.UserEmail)] = i
+		}
+	}
+
+	var adds, updates, removes []Action
+	matched := make(map[int]bool)
+	for _, member := range desired {
+		i, ok := byID[member.UserID]
+		if member.UserID == "" {
+			i, ok = byEmail[strings.ToLower(member.Email)]
+		}
+		if !ok {
+			adds = append(adds, Action{Type: ActionAdd, Team: team, Member: member})
+			continue
+		}
+
+		matched[i] = true
+		if member.UserID == "" {
+			member.UserID = current[i].UserID
+		}
+		if current[i].Role != member.Role {
+			updates = append(updates, Action{Type: ActionUpdateRole, Team: team, Member: member, OldRole: current[i].Role})
+		}
+	}
+
+	if !opts.NoRemove {
+		for i, member := range current {
+			if matched[i] {
+				continue
+			}
+			removes = append(removes, Action{Type: ActionRemove, Team: team, Member: Member{
+				UserID: member.UserID,
+				Email:  member.UserEmail,
+				Role:   member.Role,
+			}})
+		}
+	}
+
+	return append(append(adds, updates...), removes...)
+}
@@ -0,0 +1,205 @@
+// Package roster reads team membership rosters and plans the changes needed
+// to make teams match them.
+package roster
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRole is the role given to members whose roster entry has none
+const DefaultRole = "user"
+
+// Member is a desired team member. Either UserID or Email is set.
+type Member struct {
+	UserID string `json:"user_id,omitempty" yaml:"user_id,omitempty"`
+	Email  string `json:"email,omitempty" yaml:"email,omitempty"`
+	Role   string `json:"role" yaml:"role"`
+}
+
+// Team lists the desired members of a team, identified by ID or alias
+type Team struct {
+	Team    string   `yaml:"team"`
+	Members []Member `yaml:"members"`
+}
+
+// Roster is the desired membership of one or more teams
+type Roster struct {
+	Teams []Team `yaml:"teams"`
+}
+
+// Load reads a roster from a .csv, .yaml or .yml file
+func Load(path string) (*Roster, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var roster *Roster
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		roster, err = ParseCSV(file)
+	case ".yaml", ".yml":
+		roster, err = ParseYAML(file)
+	default:
+		return nil, fmt.Errorf("unsupported roster format %q, use .csv, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return roster, nil
+}
+
+// ParseCSV reads a roster with a header row naming the columns team, user_id,
+// email and role. Only team and one of user_id or email are required; rows
+// are grouped by team in the order teams first appear.
+func ParseCSV(r io.Reader) (*Roster, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("roster is empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["team"]; !ok {
+		return nil, errors.New("missing team column")
+	}
+	_, hasUserID := columns["user_id"]
+	_, hasEmail := columns["email"]
+	if !hasUserID && !hasEmail {
+		return nil, errors.New("missing user_id or email column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	roster := &Roster{}
+	teams := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		team := field(record, "team")
+		if team == "" {
+			return nil, fmt.Errorf("line %d: team is empty", line)
+		}
+		i, ok := teams[team]
+		if !ok {
+			i = len(roster.Teams)
+			teams[team] = i
+			roster.Teams = append(roster.Teams, Team{Team: team})
+		}
+		roster.Teams[i].Members = append(roster.Teams[i].Members, Member{
+			UserID: field(record, "user_id"),
+			Email:  field(record, "email"),
+			Role:   field(record, "role"),
+		})
+	}
+
+	return roster, roster.Normalize()
+}
+
+// ParseYAML reads a roster of the form
+//
+//	teams:
+//	  - team: CHAT
+//	    members:
+//	      - email: jdoe@example.com
+//	        role: admin
+//	      - user_id: user_123
+func ParseYAML(r io.Reader) (*Roster, error) {
+	var roster Roster
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&roster); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("roster is empty")
+		}
+		return nil, err
+	}
+	return &roster, roster.Normalize()
+}
+
+// Normalize fills in default roles and rejects incomplete or duplicate
+// entries. Load and the parsers call it; rosters built in code should too.
+func (r *Roster) Normalize() error {
+	seenTeams := make(map[string]bool)
+	for i := range r.Teams {
+		team := &r.Teams[i]
+		if team.Team == "" {
+			return fmt.Errorf("team %d has no name", i+1)
+		}
+		if seenTeams[team.Team] {
+			return fmt.Errorf("team %s is listed more than once", team.Team)
+		}
+		seenTeams[team.Team] = true
+
+		seen := make(map[string]bool)
+		for j := range team.Members {
+			member := &team.Members[j]
+			if member.UserID == "" && member.Email == "" {
+				return fmt.Errorf("team %s: member %d has neither user_id nor email", team.Team, j+1)
+			}
+			if member.Role == "" {
+				member.Role = DefaultRole
+			}
+			if member.Role != "admin" && member.Role != "user" {
+				return fmt.Errorf("team %s: member %s has role %q, must be admin or user", team.Team, member, member.Role)
+			}
+
+			key := member.key()
+			if seen[key] {
+				return fmt.Errorf("team %s: member %s is listed more than once", team.Team, member)
+			}
+			seen[key] = true
+		}
+	}
+	return nil
+}
+
+// key identifies a member by user ID, or by lower-cased email without one
+func (m Member) key() string {
+	if m.UserID != "" {
+		return "id:" + m.UserID
+	}
+	return "email:" + strings.ToLower(m.Email)
+}
+
+// String returns the member's email and user ID for display
+func (m Member) String() string {
+	switch {
+	case m.UserID != "" && m.Email != "":
+		return fmt.Sprintf("%s (%s)", m.Email, m.UserID)
+	case m.UserID != "":
+		return m.UserID
+	default:
+		return m.Email
+	}
+}
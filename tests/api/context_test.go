@@ -0,0 +1,59 @@
+// tests/api/context_test.go
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestListTeamKeysContext_CancelReturnsPartialResults(t *testing.T) {
+	const teamID = "0dbaa4dd-8523-4e05-8d43-91b7dd80f671"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/key/list":
+			w.Write([]byte(`{"keys": ["hash-1", "hash-2", "hash-3"], "total_count": 3, "current_page": 1, "total_pages": 1}`))
+		case "/key/info":
+			if r.URL.Query().Get("key") == "hash-1" {
+				w.Write([]byte(`{"key": "hash-1", "info": {"key_alias": "first"}}`))
+				return
+			}
+			// Simulate Ctrl-C while the second key is in flight
+			cancel()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	result, err := client.ListTeamKeysContext(ctx, teamID, api.KeyListOptions{Concurrency: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(result.Keys) != 1 || result.Keys[0].Info.KeyAlias != "first" {
+		t.Errorf("Expected the first key as a partial result, got %+v", result.Keys)
+	}
+}
+
+func TestListTeamsContext_AlreadyCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not reach the server once the context is cancelled")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := api.NewClient(server.URL, "sk-test")
+	if _, err := client.ListTeamsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+// tests/api/errors_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func newErrorServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestAPIError_LiteLLMEnvelope(t *testing.T) {
+	server := newErrorServer(http.StatusUnauthorized, `{"error": {"message": "Authentication Error, invalid key", "type": "auth_error", "code": "401"}}`)
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	_, err := client.ListTeams()
+
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *api.APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Method != http.MethodGet || apiErr.Path != "/team/list" {
+		t.Errorf("Unexpected error fields: %+v", apiErr)
+	}
+	if apiErr.Code != "401" || apiErr.Message != "Authentication Error, invalid key" {
+		t.Errorf("Expected code and message from envelope, got %q / %q", apiErr.Code, apiErr.Message)
+	}
+	if !api.IsUnauthorized(err) || api.IsNotFound(err) {
+		t.Errorf("Expected IsUnauthorized to match 401")
+	}
+}
+
+func TestAPIError_FlatAndDetail(t *testing.T) {
+	tests := []struct {
+		body    string
+		code    string
+		message string
+	}{
+		{`{"code": "team_not_found", "message": "Team does not exist"}`, "team_not_found", "Team does not exist"},
+		{`{"detail": "Not Found"}`, "", "Not Found"},
+		{`{"detail": {"error": "Team not found, passed team_id=abc"}}`, "", "Team not found, passed team_id=abc"},
+	}
+
+	for _, tt := range tests {
+		server := newErrorServer(http.StatusNotFound, tt.body)
+		client := api.NewClient(server.URL, "sk-test")
+		_, err := client.GetKeyInfo("sk-missing")
+		server.Close()
+
+		var apiErr *api.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected *api.APIError for %s, got %v", tt.body, err)
+		}
+		if apiErr.Code != tt.code || apiErr.Message != tt.message {
+			t.Errorf("Body %s: expected %q / %q, got %q / %q", tt.body, tt.code, tt.message, apiErr.Code, apiErr.Message)
+		}
+		if !api.IsNotFound(err) {
+			t.Errorf("Expected IsNotFound for 404")
+		}
+	}
+}
+
+func TestAPIError_NonJSONBody(t *testing.T) {
+	server := newErrorServer(http.StatusBadGateway, "<html><body>502 Bad Gateway</body></html>")
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	client.Retry = api.RetryPolicy{}
+	_, err := client.GetUserInfo("user_123")
+
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *api.APIError, got %T: %v", err, err)
+	}
+	if !strings.Contains(string(apiErr.Body), "502 Bad Gateway") {
+		t.Errorf("Expected raw body to be preserved, got %q", apiErr.Body)
+	}
+	if !api.IsServerError(err) {
+		t.Errorf("Expected IsServerError for 502")
+	}
+	if !strings.HasPrefix(err.Error(), "API error:") {
+		t.Errorf("Expected error message to start with 'API error:', got %s", err)
+	}
+}
+
+func TestError_KeptForCompatibility(t *testing.T) {
+	// api.Error is deprecated but still decodes the flat error format for
+	// code written against earlier releases
+	var legacy api.Error
+	if err := json.Unmarshal([]byte(`{"code": "400", "message": "bad request"}`), &legacy); err != nil {
+		t.Fatalf("Expected api.Error to decode, got %v", err)
+	}
+	if legacy.Code != "400" || legacy.Message != "bad request" {
+		t.Errorf("Unexpected api.Error: %+v", legacy)
+	}
+}
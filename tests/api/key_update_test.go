@@ -0,0 +1,51 @@
+// tests/api/key_update_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestUpdateKey_SendsUnsetFieldsAsNull(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key/update" || r.Method != http.MethodPost {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"key": "hash-1"}`))
+	}))
+	defer server.Close()
+
+	maxBudget := 25.0
+	client := api.NewClient(server.URL, "sk-test")
+	_, err := client.UpdateKey(api.UpdateKeyRequest{
+		Key:       "hash-1",
+		MaxBudget: &maxBudget,
+		Models:    []string{},
+		Unset:     []string{"tpm_limit"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateKey failed: %v", err)
+	}
+
+	if body["max_budget"] != 25.0 {
+		t.Errorf("Expected max_budget 25, got %v", body["max_budget"])
+	}
+	if value, ok := body["tpm_limit"]; !ok || value != nil {
+		t.Errorf("Expected tpm_limit to be sent as null, got %v (present: %v)", value, ok)
+	}
+	if models, ok := body["models"].([]interface{}); !ok || len(models) != 0 {
+		t.Errorf("Expected an empty models list, got %v", body["models"])
+	}
+	if _, ok := body["rpm_limit"]; ok {
+		t.Errorf("Expected rpm_limit to be omitted")
+	}
+}
@@ -0,0 +1,186 @@
+// tests/api/keys_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+// newResolveServer answers /key/info for known hashed tokens and /key/list
+// with every key, ignoring the key_alias filter as older servers do
+func newResolveServer(t *testing.T, infoRequests *[]string) *httptest.Server {
+	secretHash := api.HashToken("sk-secret")
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/key/info":
+			key := r.URL.Query().Get("key")
+			*infoRequests = append(*infoRequests, key)
+			if key != secretHash {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"detail": "key not found"}`))
+				return
+			}
+			w.Write([]byte(`{"key": "` + key + `", "info": {"key_alias": "from-secret", "team_id": "team-chat"}}`))
+		case "/key/list":
+			w.Write([]byte(`{"keys": [
+				{"token": "hash-ci", "key_alias": "ci-bot"},
+				{"token": "hash-dup-1", "key_alias": "shared"},
+				{"token": "hash-dup-2", "key_alias": "shared"},
+				{"token": "hash-prefix", "key_alias": "ci-bot-old"}
+			], "total_count": 4, "current_page": 1, "total_pages": 1}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestResolveKey(t *testing.T) {
+	var infoRequests []string
+	server := newResolveServer(t, &infoRequests)
+	defer server.Close()
+	client := api.NewClient(server.URL, "sk-test")
+
+	// Secrets are hashed before they are sent
+	key, err := client.ResolveKey("sk-secret")
+	if err != nil {
+		t.Fatalf("ResolveKey by secret failed: %v", err)
+	}
+	if key.Token != api.HashToken("sk-secret") || key.KeyAlias != "from-secret" {
+		t.Errorf("Unexpected key for the secret: %+v", key)
+	}
+	if len(infoRequests) != 1 || strings.HasPrefix(infoRequests[0], "sk-") {
+		t.Errorf("Expected the hashed token to be looked up, got %v", infoRequests)
+	}
+
+	key, err = client.ResolveKey(api.HashToken("sk-secret"))
+	if err != nil {
+		t.Fatalf("ResolveKey by hashed token failed: %v", err)
+	}
+	if key.Token != api.HashToken("sk-secret") {
+		t.Errorf("Unexpected key for the hashed token: %+v", key)
+	}
+
+	key, err = client.ResolveKey("ci-bot")
+	if err != nil {
+		t.Fatalf("ResolveKey by alias failed: %v", err)
+	}
+	if key.Token != "hash-ci" {
+		t.Errorf("Expected only the exact alias to match, got %+v", key)
+	}
+
+	if _, err := client.ResolveKey("shared"); err == nil || !strings.Contains(err.Error(), "matches 2 keys") {
+		t.Errorf("Expected an ambiguous alias to fail, got %v", err)
+	}
+	if _, err := client.ResolveKey("missing"); err == nil || !strings.Contains(err.Error(), "key not found") {
+		t.Errorf("Expected an unknown alias to fail, got %v", err)
+	}
+	if _, err := client.ResolveKey("sk-unknown"); !api.IsNotFound(err) {
+		t.Errorf("Expected an unknown secret to fail with 404, got %v", err)
+	}
+}
+
+func TestDeleteBlockUnblockKeys_RequestBodies(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/key/delete":
+			w.Write([]byte(`{"deleted_keys": ["hash-1", "hash-2"]}`))
+		case "/key/block":
+			w.Write([]byte(`{"key_alias": "ci-bot", "blocked": true}`))
+		case "/key/unblock":
+			w.Write([]byte(`{"key_alias": "ci-bot", "blocked": false}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(server.URL, "sk-test")
+
+	deleted, err := client.DeleteKeys([]string{"hash-1", "hash-2"})
+	if err != nil {
+		t.Fatalf("DeleteKeys failed: %v", err)
+	}
+	if len(deleted.DeletedKeys) != 2 {
+		t.Errorf("Expected 2 deleted keys, got %+v", deleted)
+	}
+	if keys, _ := bodies["/key/delete"]["keys"].([]interface{}); len(keys) != 2 || keys[0] != "hash-1" || keys[1] != "hash-2" {
+		t.Errorf("Unexpected /key/delete body: %v", bodies["/key/delete"])
+	}
+
+	if _, err := client.BlockKey("hash-1"); err != nil {
+		t.Fatalf("BlockKey failed: %v", err)
+	}
+	if body := bodies["/key/block"]; len(body) != 1 || body["key"] != "hash-1" {
+		t.Errorf("Unexpected /key/block body: %v", body)
+	}
+
+	if _, err := client.UnblockKey("hash-2"); err != nil {
+		t.Fatalf("UnblockKey failed: %v", err)
+	}
+	if body := bodies["/key/unblock"]; len(body) != 1 || body["key"] != "hash-2" {
+		t.Errorf("Unexpected /key/unblock body: %v", body)
+	}
+}
+
+func TestGenerateKey_RequestBody(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/team/list":
+			w.Write([]byte(`[{"team_id": "team-chat", "team_alias": "CHAT"}, {"team_id": "team-cline", "team_alias": "CLINE"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/key/generate":
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"key": "sk-new", "key_alias": "ci-bot", "token_id": "hash-new", "team_id": "team-chat"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient(server.URL, "sk-test")
+
+	maxBudget := 25.0
+	rpmLimit := int64(60)
+	result, err := client.GenerateKey(api.GenerateKeyRequest{
+		KeyAlias:       "ci-bot",
+		TeamID:         "CHAT",
+		Models:         []string{"gpt-4.1", "gpt-4.1-mini"},
+		MaxBudget:      &maxBudget,
+		BudgetDuration: "30d",
+		RPMLimit:       &rpmLimit,
+		Duration:       "90d",
+		Metadata:       map[string]interface{}{"env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	if result.Key != "sk-new" || result.HashedToken() != "hash-new" {
+		t.Errorf("Unexpected response: %+v", result)
+	}
+
+	expected := map[string]interface{}{
+		"key_alias":       "ci-bot",
+		"team_id":         "team-chat",
+		"models":          []interface{}{"gpt-4.1", "gpt-4.1-mini"},
+		"max_budget":      25.0,
+		"budget_duration": "30d",
+		"rpm_limit":       60.0,
+		"duration":        "90d",
+		"metadata":        map[string]interface{}{"env": "prod"},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("Unexpected /key/generate body:\n got %v\nwant %v", body, expected)
+	}
+}
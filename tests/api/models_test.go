@@ -0,0 +1,151 @@
+// tests/api/models_test.go
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestModelAllowed(t *testing.T) {
+	tests := []struct {
+		model   string
+		allowed []string
+		want    bool
+	}{
+		{"gpt-4.1", nil, true},
+		{"gpt-4.1", []string{"all-proxy-models"}, true},
+		{"gpt-4.1", []string{"gpt-4.1-mini", "gpt-4.1"}, true},
+		{"gpt-4.1", []string{"gpt-4.1-mini"}, false},
+		{"openai/gpt-4o", []string{"openai/*"}, true},
+		{"anthropic/claude", []string{"openai/*"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := api.ModelAllowed(tt.model, tt.allowed); got != tt.want {
+			t.Errorf("ModelAllowed(%q, %v) = %v, want %v", tt.model, tt.allowed, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLiveness(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`"I'm alive!"`))
+	}))
+	defer server.Close()
+
+	if err := api.NewClient(server.URL, "").CheckLiveness(); err != nil {
+		t.Fatalf("CheckLiveness failed: %v", err)
+	}
+	if path != "/health/liveliness" {
+		t.Errorf("Expected /health/liveliness, got %s", path)
+	}
+
+	missing := newErrorServer(http.StatusNotFound, "Not Found")
+	defer missing.Close()
+	if err := api.NewClient(missing.URL, "").CheckLiveness(); !api.IsNotFound(err) {
+		t.Errorf("Expected a 404 APIError, got %v", err)
+	}
+}
+
+// newModelServer answers GET path with body after checking the API key
+// header, and records the query of the last request
+func newModelServer(t *testing.T, path, body string, query *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != path {
+			t.Errorf("Expected GET %s, got %s %s", path, r.Method, r.URL.Path)
+		}
+		if key := r.Header.Get("x-litellm-api-key"); key != "sk-test" {
+			t.Errorf("Expected the x-litellm-api-key header, got %q", key)
+		}
+		*query = r.URL.Query()
+		w.Write([]byte(body))
+	}))
+}
+
+func TestListModels(t *testing.T) {
+	var query url.Values
+	server := newModelServer(t, "/models", `{"object": "list", "data": [
+		{"id": "gpt-4.1", "object": "model", "created": 1677610602, "owned_by": "openai"},
+		{"id": "gpt-4.1-mini", "object": "model", "created": 1677610602, "owned_by": "openai"}
+	]}`, &query)
+	defer server.Close()
+
+	models, err := api.NewClient(server.URL, "sk-test").ListModels()
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if query.Get("return_wildcard_routes") != "false" || query.Get("include_model_access_groups") != "false" {
+		t.Errorf("Expected wildcard routes and access groups to be excluded, got %v", query)
+	}
+	if models.Object != "list" || len(models.Data) != 2 {
+		t.Fatalf("Unexpected response: %+v", models)
+	}
+	if model := models.Data[1]; model.ID != "gpt-4.1-mini" || model.OwnedBy != "openai" || model.Created != 1677610602 {
+		t.Errorf("Unexpected model: %+v", model)
+	}
+}
+
+func TestGetModelInfo(t *testing.T) {
+	var query url.Values
+	server := newModelServer(t, "/model/info", `{"data": [{
+		"model_name": "gpt-4.1",
+		"litellm_params": {"api_base": "https://example.openai.azure.com", "api_version": "2024-10-21", "custom_llm_provider": "azure", "input_cost_per_token": 0.000002, "output_cost_per_token": 0.000008},
+		"model_info": {"id": "deployment-1", "base_model": "azure/gpt-4.1", "mode": "chat", "max_tokens": 32768, "litellm_provider": "azure", "supports_vision": true, "supports_function_calling": true}
+	}]}`, &query)
+	defer server.Close()
+
+	info, err := api.NewClient(server.URL, "sk-test").GetModelInfo()
+	if err != nil {
+		t.Fatalf("GetModelInfo failed: %v", err)
+	}
+	if len(query) != 0 {
+		t.Errorf("Expected no query parameters, got %v", query)
+	}
+	if len(info.Data) != 1 {
+		t.Fatalf("Expected 1 deployment, got %+v", info)
+	}
+	model := info.Data[0]
+	if model.ModelName != "gpt-4.1" || model.LitellmParams.ApiBase != "https://example.openai.azure.com" ||
+		model.LitellmParams.CustomProvider != "azure" || model.LitellmParams.OutputCostPerToken != 0.000008 {
+		t.Errorf("Unexpected model params: %+v", model)
+	}
+	if details := model.ModelInfo; details.ID != "deployment-1" || details.MaxTokens != 32768 ||
+		!details.SupportsVision || !details.SupportsFunction || details.SupportsStreaming {
+		t.Errorf("Unexpected model details: %+v", details)
+	}
+}
+
+func TestGetModelHealth(t *testing.T) {
+	var query url.Values
+	server := newModelServer(t, "/health", `{
+		"healthy_endpoints": [{"api_base": "https://east.example.com", "custom_llm_provider": "azure", "x-ms-region": "East US", "x-ratelimit-remaining-requests": "99"}],
+		"unhealthy_endpoints": [{"api_base": "https://west.example.com", "custom_llm_provider": "azure"}],
+		"healthy_count": 1,
+		"unhealthy_count": 1
+	}`, &query)
+	defer server.Close()
+
+	health, err := api.NewClient(server.URL, "sk-test").GetModelHealth("gpt-4.1")
+	if err != nil {
+		t.Fatalf("GetModelHealth failed: %v", err)
+	}
+	if query.Get("model") != "gpt-4.1" {
+		t.Errorf("Expected model=gpt-4.1, got %v", query)
+	}
+	if health.HealthyCount != 1 || health.UnhealthyCount != 1 || len(health.HealthyEndpoints) != 1 || len(health.UnhealthyEndpoints) != 1 {
+		t.Fatalf("Unexpected response: %+v", health)
+	}
+	if endpoint := health.HealthyEndpoints[0]; endpoint.ApiBase != "https://east.example.com" || endpoint.XMsRegion != "East US" || endpoint.XRateLimitRemainingReqs != "99" {
+		t.Errorf("Unexpected healthy endpoint: %+v", endpoint)
+	}
+	if endpoint := health.UnhealthyEndpoints[0]; endpoint.ApiBase != "https://west.example.com" {
+		t.Errorf("Unexpected unhealthy endpoint: %+v", endpoint)
+	}
+}
@@ -0,0 +1,125 @@
+// tests/api/pagination_test.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+// newKeyListServer serves total keys named key-1..key-N in pages of the
+// requested size, as /key/list does with return_full_object=true. Odd keys
+// belong to user-odd and even keys to user-even; the user_id filter is
+// ignored, as older servers do.
+func newKeyListServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		if r.URL.Query().Get("return_full_object") != "true" {
+			t.Errorf("Expected return_full_object=true, got %s", r.URL.RawQuery)
+		}
+
+		totalPages := (total + size - 1) / size
+		keys := "["
+		for i := (page-1)*size + 1; i <= page*size && i <= total; i++ {
+			if len(keys) > 1 {
+				keys += ","
+			}
+			owner := "user-odd"
+			if i%2 == 0 {
+				owner = "user-even"
+			}
+			keys += fmt.Sprintf(`{"token": "hash-%d", "key_alias": "key-%d", "user_id": "%s"}`, i, i, owner)
+		}
+		keys += "]"
+		fmt.Fprintf(w, `{"keys": %s, "total_count": %d, "current_page": %d, "total_pages": %d}`, keys, total, page, totalPages)
+	}))
+}
+
+func TestKeysIter_WalksEveryPage(t *testing.T) {
+	server := newKeyListServer(t, 7)
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	iter := client.KeysIter(context.Background(), api.KeyListOptions{PageSize: 3})
+
+	var aliases []string
+	for iter.Next() {
+		aliases = append(aliases, iter.Key().KeyAlias)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(aliases) != 7 || aliases[0] != "key-1" || aliases[6] != "key-7" {
+		t.Errorf("Expected key-1..key-7, got %v", aliases)
+	}
+	if iter.TotalCount() != 7 {
+		t.Errorf("Expected total count 7, got %d", iter.TotalCount())
+	}
+}
+
+func TestListKeys_SinglePageAndAllPages(t *testing.T) {
+	server := newKeyListServer(t, 250)
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+
+	page, err := client.ListKeys(api.KeyListOptions{Page: 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Keys) != 50 || page.TotalPages != 3 || page.Keys[0].KeyAlias != "key-201" {
+		t.Errorf("Expected the last 50 keys on page 3, got %d keys starting at %s", len(page.Keys), page.Keys[0].KeyAlias)
+	}
+
+	all, err := client.ListAllKeys(api.KeyListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(all) != 250 {
+		t.Errorf("Expected all 250 keys, got %d", len(all))
+	}
+}
+
+func TestKeysIter_EmptyListing(t *testing.T) {
+	server := newKeyListServer(t, 0)
+	defer server.Close()
+
+	iter := api.NewClient(server.URL, "sk-test").KeysIter(context.Background(), api.KeyListOptions{})
+	if iter.Next() {
+		t.Errorf("Expected no keys, got %+v", iter.Key())
+	}
+	if err := iter.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestListKeys_FiltersByUserWhenTheServerDoesNot(t *testing.T) {
+	server := newKeyListServer(t, 7)
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+
+	// Every other page holds no key of user-even once filtered
+	all, err := client.ListAllKeys(api.KeyListOptions{UserID: "user-even", PageSize: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(all) != 3 || all[0].KeyAlias != "key-2" || all[2].KeyAlias != "key-6" {
+		t.Errorf("Expected only the keys of user-even, got %+v", all)
+	}
+
+	page, err := client.ListKeys(api.KeyListOptions{UserID: "user-odd", PageSize: 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Keys) != 2 || page.Keys[0].KeyAlias != "key-1" || page.Keys[1].KeyAlias != "key-3" {
+		t.Errorf("Expected only the keys of user-odd on page 1, got %+v", page.Keys)
+	}
+}
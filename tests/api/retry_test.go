@@ -0,0 +1,153 @@
+// tests/api/retry_test.go
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func newFlakyServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"team_id": "team-1"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	return server, &calls
+}
+
+func newFastRetryClient(url string) *api.Client {
+	client := api.NewClient(url, "sk-test")
+	client.Retry = api.RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+	return client
+}
+
+func TestRetry_GetRecoversFromTransientErrors(t *testing.T) {
+	server, calls := newFlakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	var log bytes.Buffer
+	client := newFastRetryClient(server.URL)
+	client.Verbose = &log
+
+	if _, err := client.ListTeams(); err != nil {
+		t.Fatalf("Expected request to succeed after retries, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+	if !strings.Contains(log.String(), "attempt 3/3") {
+		t.Errorf("Expected verbose output to report attempts, got: %s", log.String())
+	}
+}
+
+func TestRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := newFlakyServer(10, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	_, err := newFastRetryClient(server.URL).ListTeams()
+	if !api.IsServerError(err) {
+		t.Fatalf("Expected a 502 APIError, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestRetry_PostOnlyWhenEnabled(t *testing.T) {
+	const teamID = "0dbaa4dd-8523-4e05-8d43-91b7dd80f671"
+	member := api.TeamMember{UserID: "user_1", Role: "user"}
+
+	server, calls := newFlakyServer(1, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	client := newFastRetryClient(server.URL)
+	if _, err := client.AddTeamMember(teamID, member); err == nil {
+		t.Fatalf("Expected POST to fail without retry")
+	}
+	if *calls != 1 {
+		t.Errorf("Expected a single attempt for POST, got %d", *calls)
+	}
+
+	atomic.StoreInt32(calls, 0)
+	client.Retry.RetryMutations = true
+	if _, err := client.AddTeamMember(teamID, member); err != nil {
+		t.Fatalf("Expected POST to succeed with mutation retries, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 attempts for POST, got %d", *calls)
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	server, _ := newFlakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	defer server.Close()
+
+	client := newFastRetryClient(server.URL)
+	client.Retry.MaxBackoff = 2 * time.Second
+	start := time.Now()
+	if _, err := client.ListTeams(); err != nil {
+		t.Fatalf("Expected request to succeed after retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Expected to wait for Retry-After, only waited %s", elapsed)
+	}
+}
+
+func TestRetry_HonorsRateLimitReset(t *testing.T) {
+	server, _ := newFlakyServer(1, http.StatusTooManyRequests, http.Header{"X-Ratelimit-Reset-Requests": {"300ms"}})
+	defer server.Close()
+
+	client := newFastRetryClient(server.URL)
+	client.Retry.MaxBackoff = 2 * time.Second
+	start := time.Now()
+	if _, err := client.ListTeams(); err != nil {
+		t.Fatalf("Expected request to succeed after retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("Expected to wait for x-ratelimit-reset-requests, only waited %s", elapsed)
+	}
+}
+
+func TestRetry_GivesUpWhenServerDelayExceedsMaxBackoff(t *testing.T) {
+	for _, header := range []http.Header{
+		{"Retry-After": {"3600"}},
+		{"X-Ratelimit-Reset-Tokens": {"6m0s"}},
+	} {
+		server, calls := newFlakyServer(1, http.StatusTooManyRequests, header)
+
+		start := time.Now()
+		_, err := newFastRetryClient(server.URL).ListTeams()
+		server.Close()
+		if !api.IsRateLimited(err) {
+			t.Errorf("%v: expected a 429 APIError, got %v", header, err)
+		}
+		if *calls != 1 {
+			t.Errorf("%v: expected a single attempt, got %d", header, *calls)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%v: expected to give up right away, waited %s", header, elapsed)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// tests/api/rotate_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestRotateKey_Regenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key/hash-old/regenerate" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		metadata, _ := body["metadata"].(map[string]interface{})
+		if metadata["owner"] != "platform" || metadata["rotated_at"] != "2025-03-01T00:00:00Z" {
+			t.Errorf("Expected existing metadata plus rotated_at, got %v", metadata)
+		}
+		w.Write([]byte(`{"key": "sk-new", "token": "hash-new"}`))
+	}))
+	defer server.Close()
+
+	key := api.KeyObject{Token: "hash-old", KeyInfo: api.KeyInfo{KeyAlias: "ci-bot", Metadata: map[string]interface{}{"owner": "platform"}}}
+	client := api.NewClient(server.URL, "sk-test")
+	result, err := client.RotateKey(key, api.RotateKeyOptions{Now: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if result.Method != api.RotateMethodRegenerate || result.Secret != "sk-new" || result.NewToken != "hash-new" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestRotateKey_GracePeriodGeneratesCopy(t *testing.T) {
+	var calls []string
+	var generated api.GenerateKeyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/key/update":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if duration, ok := body["duration"]; ok {
+				if duration != "24h" {
+					t.Errorf("Expected the grace period as duration, got %v", duration)
+				}
+				w.Write([]byte(`{"key": "hash-old", "expires": "2025-03-02T00:00:00Z"}`))
+				return
+			}
+			w.Write([]byte(`{"key": "hash-old"}`))
+		case "/key/generate":
+			json.NewDecoder(r.Body).Decode(&generated)
+			w.Write([]byte(`{"key": "sk-new", "token": "hash-new"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	maxBudget := 50.0
+	key := api.KeyObject{Token: "hash-old", KeyInfo: api.KeyInfo{
+		KeyAlias:  "ci-bot",
+		TeamID:    testTeamID,
+		Models:    []string{"gpt-4.1"},
+		MaxBudget: &maxBudget,
+	}}
+	client := api.NewClient(server.URL, "sk-test")
+	result, err := client.RotateKey(key, api.RotateKeyOptions{GracePeriod: "24h", Now: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+
+	if len(calls) != 3 || calls[0] != "/key/update" || calls[1] != "/key/generate" || calls[2] != "/key/update" {
+		t.Errorf("Expected rename, generate, grace period update; got %v", calls)
+	}
+	if generated.KeyAlias != "ci-bot" || generated.MaxBudget == nil || *generated.MaxBudget != 50 || len(generated.Models) != 1 {
+		t.Errorf("Expected the new key to copy the old key's settings, got %+v", generated)
+	}
+	if result.Method != api.RotateMethodGenerate || result.OldKeyAlias != "ci-bot-rotated-20250301000000" || result.OldKeyExpires != "2025-03-02T00:00:00Z" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestRotateKey_FallsBackOnlyWhenRegenerateIsUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		fallback bool
+	}{
+		{"missing endpoint", http.StatusNotFound, `{"detail": "Not Found"}`, true},
+		{"method not allowed", http.StatusMethodNotAllowed, `{"detail": "Method Not Allowed"}`, true},
+		{"enterprise license", http.StatusForbidden, `{"error": {"message": "Regenerating Virtual Keys is an Enterprise feature, You must be a LiteLLM Enterprise user to use this feature.", "code": "403"}}`, true},
+		{"permission denied", http.StatusForbidden, `{"error": {"message": "Only proxy admins and key owners can regenerate keys", "code": "403"}}`, false},
+	}
+
+	for _, tt := range tests {
+		var calls []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.URL.Path)
+			switch r.URL.Path {
+			case "/key/hash-old/regenerate":
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			case "/key/update":
+				w.Write([]byte(`{"key": "hash-old"}`))
+			case "/key/generate":
+				w.Write([]byte(`{"key": "sk-new", "token": "hash-new"}`))
+			case "/key/delete":
+				w.Write([]byte(`{"deleted_keys": ["hash-old"]}`))
+			default:
+				t.Errorf("%s: unexpected request %s %s", tt.name, r.Method, r.URL.Path)
+			}
+		}))
+
+		key := api.KeyObject{Token: "hash-old", KeyInfo: api.KeyInfo{KeyAlias: "ci-bot"}}
+		result, err := api.NewClient(server.URL, "sk-test").RotateKey(key, api.RotateKeyOptions{})
+		server.Close()
+
+		if !tt.fallback {
+			if !api.IsForbidden(err) {
+				t.Errorf("%s: expected the 403 to be returned, got %v", tt.name, err)
+			}
+			if len(calls) != 1 {
+				t.Errorf("%s: expected no fallback requests, got %v", tt.name, calls)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: expected a fallback rotation, got %v", tt.name, err)
+			continue
+		}
+		if result.Method != api.RotateMethodGenerate || result.NewToken != "hash-new" {
+			t.Errorf("%s: unexpected result %+v", tt.name, result)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// tests/api/team_keys_test.go
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+const testTeamID = "0dbaa4dd-8523-4e05-8d43-91b7dd80f671"
+
+func TestListTeamKeys_FullObjectsSkipKeyInfo(t *testing.T) {
+	var infoCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/key/list":
+			w.Write([]byte(`{"keys": [{"token": "hash-1", "key_alias": "one"}, {"token": "hash-2", "key_alias": "two"}], "total_count": 2, "current_page": 1, "total_pages": 1}`))
+		case "/key/info":
+			atomic.AddInt32(&infoCalls, 1)
+		}
+	}))
+	defer server.Close()
+
+	result, err := api.NewClient(server.URL, "sk-test").ListTeamKeys(testTeamID, api.KeyListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Keys) != 2 || result.Keys[0].Key != "hash-1" || result.Keys[1].Info.KeyAlias != "two" {
+		t.Errorf("Unexpected keys: %+v", result.Keys)
+	}
+	if infoCalls != 0 {
+		t.Errorf("Expected no /key/info calls with full objects, got %d", infoCalls)
+	}
+}
+
+func TestListTeamKeys_FallbackCollectsWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/key/list":
+			w.Write([]byte(`{"keys": ["hash-1", "hash-2", "hash-3", "hash-4"], "total_count": 4, "current_page": 1, "total_pages": 1}`))
+		case "/key/info":
+			key := r.URL.Query().Get("key")
+			if key == "hash-3" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"detail": "key not found"}`))
+				return
+			}
+			w.Write([]byte(`{"key": "` + key + `", "info": {"key_alias": "alias-` + key + `"}}`))
+		}
+	}))
+	defer server.Close()
+
+	result, err := api.NewClient(server.URL, "sk-test").ListTeamKeys(testTeamID, api.KeyListOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("Expected partial results without error, got %v", err)
+	}
+
+	var got []string
+	for _, key := range result.Keys {
+		got = append(got, key.Key)
+	}
+	if len(got) != 3 || got[0] != "hash-1" || got[1] != "hash-2" || got[2] != "hash-4" {
+		t.Errorf("Expected hash-1, hash-2 and hash-4 in listing order, got %v", got)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Key != "hash-3" || !api.IsNotFound(result.Warnings[0]) {
+		t.Errorf("Expected a single 404 warning for hash-3, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,159 @@
+// tests/api/teams_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestUpdateTeam_ResolvesAliasAndSendsNulls(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team/list":
+			w.Write([]byte(`[{"team_id": "` + testTeamID + `", "team_alias": "CHAT"}]`))
+		case "/team/update":
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"team_id": "` + testTeamID + `", "data": {"team_id": "` + testTeamID + `", "team_alias": "CHAT", "blocked": true}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	blocked := true
+	client := api.NewClient(server.URL, "sk-test")
+	team, err := client.UpdateTeam(api.UpdateTeamRequest{TeamID: "CHAT", Blocked: &blocked, Unset: []string{"max_budget"}})
+	if err != nil {
+		t.Fatalf("UpdateTeam failed: %v", err)
+	}
+
+	if body["team_id"] != testTeamID || body["blocked"] != true {
+		t.Errorf("Unexpected request body: %v", body)
+	}
+	if value, ok := body["max_budget"]; !ok || value != nil {
+		t.Errorf("Expected max_budget to be sent as null, got %v", body)
+	}
+	if !team.Blocked || team.TeamAlias != "CHAT" {
+		t.Errorf("Expected the updated team from the response, got %+v", team)
+	}
+}
+
+func TestDeleteTeam_SendsTeamIDs(t *testing.T) {
+	var body api.DeleteTeamsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team/delete" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	if err := client.DeleteTeam(testTeamID); err != nil {
+		t.Fatalf("DeleteTeam failed: %v", err)
+	}
+	if len(body.TeamIDs) != 1 || body.TeamIDs[0] != testTeamID {
+		t.Errorf("Expected team_ids [%s], got %v", testTeamID, body.TeamIDs)
+	}
+}
+
+func TestListTeamMemberDetails_JoinsMemberships(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"team_id": "` + testTeamID + `",
+			"team_info": {"members_with_roles": [
+				{"user_id": "alice", "role": "admin"},
+				{"user_id": "bob", "role": "user"}
+			]},
+			"team_memberships": [
+				{"user_id": "bob", "spend": 12.5, "litellm_budget_table": {"max_budget": 50}}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	members, err := client.ListTeamMemberDetails(testTeamID)
+	if err != nil {
+		t.Fatalf("ListTeamMemberDetails failed: %v", err)
+	}
+
+	if len(members) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(members))
+	}
+	if members[0].UserID != "alice" || members[0].MaxBudgetInTeam != nil || members[0].Spend != 0 {
+		t.Errorf("Expected alice without a membership budget, got %+v", members[0])
+	}
+	if members[1].Spend != 12.5 || members[1].MaxBudgetInTeam == nil || *members[1].MaxBudgetInTeam != 50 {
+		t.Errorf("Expected bob's spend and budget from team_memberships, got %+v", members[1])
+	}
+}
+
+func TestUpdateTeamMember(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team/member_update" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"team_id": "` + testTeamID + `", "user_id": "bob", "max_budget_in_team": 50}`))
+	}))
+	defer server.Close()
+
+	maxBudget := 50.0
+	client := api.NewClient(server.URL, "sk-test")
+	_, err := client.UpdateTeamMember(api.UpdateTeamMemberRequest{TeamID: testTeamID, UserID: "bob", Role: "admin", MaxBudgetInTeam: &maxBudget})
+	if err != nil {
+		t.Fatalf("UpdateTeamMember failed: %v", err)
+	}
+	if body["role"] != "admin" || body["max_budget_in_team"] != 50.0 || body["user_id"] != "bob" {
+		t.Errorf("Unexpected request body: %v", body)
+	}
+	if _, ok := body["user_email"]; ok {
+		t.Errorf("Expected user_email to be omitted")
+	}
+}
+
+func TestGetTeamInfo_UsesTeamInfoEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team/info" || r.URL.Query().Get("team_id") != testTeamID {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{
+			"team_id": "` + testTeamID + `",
+			"team_info": {"team_id": "` + testTeamID + `", "team_alias": "CHAT", "max_budget": 500, "tpm_limit": 1000,
+				"budget_reset_at": "2025-03-01T00:00:00Z", "blocked": true, "metadata": {"owner": "platform"},
+				"members_with_roles": [{"user_id": "alice", "role": "admin"}]},
+			"keys": [{"token": "hash-1", "key_alias": "ci-bot", "spend": 1.5}],
+			"unknown_field": true
+		}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	team, err := client.GetTeamInfo(testTeamID)
+	if err != nil {
+		t.Fatalf("GetTeamInfo failed: %v", err)
+	}
+
+	if team.TeamAlias != "CHAT" || !team.Blocked || team.MaxBudget == nil || *team.MaxBudget != 500 || team.BudgetResetAt == "" {
+		t.Errorf("Unexpected team fields: %+v", team.Team)
+	}
+	if len(team.Members) != 1 || team.Members[0].Role != "admin" {
+		t.Errorf("Unexpected members: %+v", team.Members)
+	}
+	if len(team.Keys) != 1 || team.Keys[0].Token != "hash-1" || team.Keys[0].Spend != 1.5 {
+		t.Errorf("Unexpected keys: %+v", team.Keys)
+	}
+	if !strings.Contains(string(team.Raw), "unknown_field") {
+		t.Errorf("Expected the raw response to be kept, got %s", team.Raw)
+	}
+}
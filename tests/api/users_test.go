@@ -0,0 +1,155 @@
+// tests/api/users_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+)
+
+func TestFindUserByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/info":
+			// Servers that ignore the email parameter answer for the caller
+			w.Write([]byte(`{"user_id": "admin", "user_info": {"user_id": "admin", "user_email": "admin@example.com"}}`))
+		case "/user/list":
+			w.Write([]byte(`{"users": [{"user_id": "user_123", "user_email": "JDoe@example.com"}], "total": 1, "page": 1, "page_size": 100, "total_pages": 1}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	user, err := client.FindUserByEmail("jdoe@example.com")
+	if err != nil {
+		t.Fatalf("FindUserByEmail failed: %v", err)
+	}
+	if user.UserID != "user_123" {
+		t.Errorf("Expected user_123 from the user list, got %s", user.UserID)
+	}
+
+	if _, err := client.FindUserByEmail("nobody@example.com"); !errors.Is(err, api.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestCreateUser_DoesNotCreateKeyByDefault(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/new" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"user_id": "user_new", "user_email": "jdoe@example.com"}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	user, err := client.CreateUser(api.CreateUserRequest{UserEmail: "jdoe@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if user.UserID != "user_new" {
+		t.Errorf("Expected user_new, got %s", user.UserID)
+	}
+	if value, ok := body["auto_create_key"]; !ok || value != false {
+		t.Errorf("Expected auto_create_key false to be sent, got %v", body)
+	}
+}
+
+func TestDeleteUser_SendsUserIDs(t *testing.T) {
+	var body api.DeleteUsersRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/delete" || r.Method != http.MethodPost {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	if err := client.DeleteUser("user_123"); err != nil {
+		t.Fatalf("DeleteUser failed: %v", err)
+	}
+	if len(body.UserIDs) != 1 || body.UserIDs[0] != "user_123" {
+		t.Errorf("Expected user_ids [user_123], got %v", body.UserIDs)
+	}
+}
+
+func TestListUsers_Filters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team/list":
+			w.Write([]byte(`[{"team_id": "0dbaa4dd-8523-4e05-8d43-91b7dd80f671", "team_alias": "CLINE"}]`))
+		case "/user/list":
+			query := r.URL.Query()
+			if query.Get("role") != "internal_user" || query.Get("team") != "0dbaa4dd-8523-4e05-8d43-91b7dd80f671" || query.Get("user_email") != "EXAMPLE" {
+				t.Errorf("Expected role, team ID and email filters, got %s", r.URL.RawQuery)
+			}
+			// Ignore the filters like older servers do
+			w.Write([]byte(`{"users": [
+				{"user_id": "a", "user_email": "a@example.com", "user_role": "internal_user", "teams": ["0dbaa4dd-8523-4e05-8d43-91b7dd80f671"]},
+				{"user_id": "b", "user_email": "b@example.com", "user_role": "proxy_admin", "teams": ["0dbaa4dd-8523-4e05-8d43-91b7dd80f671"]},
+				{"user_id": "c", "user_email": "c@other.org", "user_role": "internal_user", "teams": ["0dbaa4dd-8523-4e05-8d43-91b7dd80f671"]},
+				{"user_id": "d", "user_email": "d@example.com", "user_role": "internal_user", "teams": []}
+			], "total": 4, "page": 1, "page_size": 100, "total_pages": 1}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "sk-test")
+	result, err := client.ListUsers(api.UserListOptions{Role: "internal_user", TeamID: "CLINE", Email: "EXAMPLE"})
+	if err != nil {
+		t.Fatalf("ListUsers failed: %v", err)
+	}
+	if len(result.Users) != 1 || result.Users[0].UserID != "a" {
+		t.Errorf("Expected only user a to match, got %+v", result.Users)
+	}
+}
+
+func TestUpdateUser_SendsChangesAndUnset(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/update" || r.Method != http.MethodPost {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"user_id": "user_123"}`))
+	}))
+	defer server.Close()
+
+	role := "proxy_admin"
+	client := api.NewClient(server.URL, "sk-test")
+	err := client.UpdateUser(api.UpdateUserRequest{
+		UserID:   "user_123",
+		UserRole: &role,
+		Models:   []string{},
+		Unset:    []string{"max_budget"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+
+	if body["user_id"] != "user_123" || body["user_role"] != "proxy_admin" {
+		t.Errorf("Expected user_id and user_role, got %v", body)
+	}
+	if models, ok := body["models"].([]interface{}); !ok || len(models) != 0 {
+		t.Errorf("Expected an empty models list to be sent, got %v", body["models"])
+	}
+	if value, ok := body["max_budget"]; !ok || value != nil {
+		t.Errorf("Expected max_budget null, got %v", body)
+	}
+	if _, ok := body["user_alias"]; ok {
+		t.Errorf("Expected unchanged user_alias to be omitted, got %v", body)
+	}
+}
@@ -0,0 +1,57 @@
+// tests/cmd/drift_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDrift_ReportFileIsPrivate(t *testing.T) {
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/list":
+			return http.StatusOK, `{"users": [], "total": 0, "page": 1, "page_size": 100, "total_pages": 1}`
+		case "/team/list":
+			return http.StatusOK, `[{"team_id": "team-chat", "team_alias": "CHAT", "models": ["gpt-4.1"]}]`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [], "total_count": 0, "current_page": 1, "total_pages": 1}`
+		}
+		return 0, ""
+	})
+
+	dir := t.TempDir()
+	manifests := filepath.Join(dir, "teams.yaml")
+	manifest := "apiVersion: navigatorctl/v1\nkind: Team\nmetadata:\n  name: CHAT\nspec:\n  models: [gpt-4.1]\n"
+	if err := os.WriteFile(manifests, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "drift.json")
+
+	_, stderr, err := runCLI(t, proxy, "drift", "-f", manifests, "--output", "json", "--report-file", report)
+	if err != nil {
+		t.Fatalf("drift failed: %v\n%s", err, stderr)
+	}
+	info, err := os.Stat(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(report); !strings.Contains(string(data), `"drifted": false`) {
+		t.Errorf("Expected the JSON report in the file, got %q", data)
+	}
+
+	// A second run must not overwrite the first report
+	_, stderr, err = runCLI(t, proxy, "drift", "-f", manifests, "--output", "json", "--report-file", report)
+	if err == nil {
+		t.Fatal("Expected drift to refuse an existing report file")
+	}
+	if !strings.Contains(stderr, "Error writing report") || !strings.Contains(stderr, "file exists") {
+		t.Errorf("Expected the write error, got:\n%s", stderr)
+	}
+}
@@ -0,0 +1,61 @@
+// tests/cmd/key_delete_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newKeyDeleteProxy serves a key list with a unique and an ambiguous alias
+func newKeyDeleteProxy(t *testing.T) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/key/list":
+			return http.StatusOK, `{"keys": [
+				{"token": "hash-ci", "key_alias": "ci-bot"},
+				{"token": "hash-dup-1", "key_alias": "shared"},
+				{"token": "hash-dup-2", "key_alias": "shared"}
+			], "total_count": 3, "current_page": 1, "total_pages": 1}`
+		case "/key/delete":
+			return http.StatusOK, `{"deleted_keys": ["hash-ci"]}`
+		}
+		return 0, ""
+	})
+}
+
+func TestKeyDelete_AmbiguousAliasDeletesNothing(t *testing.T) {
+	proxy := newKeyDeleteProxy(t)
+
+	_, stderr, err := runCLI(t, proxy, "key", "delete", "ci-bot", "shared", "--yes")
+	if err == nil {
+		t.Fatal("Expected key delete to fail on an ambiguous alias")
+	}
+	if !strings.Contains(stderr, "Error resolving key shared: alias shared matches 2 keys") {
+		t.Errorf("Expected the ambiguous alias to be reported, got:\n%s", stderr)
+	}
+	if deletes := proxy.received("/key/delete"); len(deletes) != 0 {
+		t.Errorf("Expected nothing to be deleted, got %+v", deletes)
+	}
+}
+
+func TestKeyDelete_DeduplicatesKeys(t *testing.T) {
+	proxy := newKeyDeleteProxy(t)
+
+	stdout, stderr, err := runCLI(t, proxy, "key", "delete", "ci-bot", "ci-bot", "--yes")
+	if err != nil {
+		t.Fatalf("key delete failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Successfully deleted 1 keys") {
+		t.Errorf("Unexpected output:\n%s", stdout)
+	}
+
+	deletes := proxy.received("/key/delete")
+	if len(deletes) != 1 {
+		t.Fatalf("Expected one /key/delete request, got %+v", deletes)
+	}
+	if keys, _ := deletes[0].Body["keys"].([]interface{}); len(keys) != 1 || keys[0] != "hash-ci" {
+		t.Errorf("Expected the key to be deleted once, got %v", deletes[0].Body)
+	}
+}
@@ -0,0 +1,56 @@
+// tests/cmd/key_generate_test.go
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyGenerate_SecretFile(t *testing.T) {
+	generated := 0
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		if r.Path == "/key/generate" {
+			generated++
+			return http.StatusOK, fmt.Sprintf(`{"key": "sk-generated-%d", "key_alias": "deploy", "token_id": "hash-deploy"}`, generated)
+		}
+		return 0, ""
+	})
+	file := filepath.Join(t.TempDir(), "deploy.key")
+
+	stdout, stderr, err := runCLI(t, proxy, "key", "generate", "--alias", "deploy", "--secret-file", file)
+	if err != nil {
+		t.Fatalf("key generate failed: %v\n%s", err, stderr)
+	}
+	if strings.Contains(stdout, "sk-generated-1") {
+		t.Errorf("Expected the secret not to be printed, got:\n%s", stdout)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "sk-generated-1\n" {
+		t.Errorf("Unexpected secret file contents %q", data)
+	}
+
+	// A second run must not overwrite the first secret, and shows the new
+	// one instead so it is not lost
+	_, stderr, err = runCLI(t, proxy, "key", "generate", "--alias", "deploy", "--secret-file", file)
+	if err == nil {
+		t.Fatal("Expected key generate to refuse an existing secret file")
+	}
+	if !strings.Contains(stderr, "file exists") || !strings.Contains(stderr, "Secret (shown once): sk-generated-2") {
+		t.Errorf("Expected the write error and the secret, got:\n%s", stderr)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "sk-generated-1\n" {
+		t.Errorf("Expected the secret file to be unchanged, got %q", data)
+	}
+}
@@ -34,7 +34,7 @@ func TestKeyInfo_MissingKey(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected error due to missing --key, got none")
 	}
-	if !strings.Contains(out.String(), "API URL, API Key, and --key are required") {
+	if !strings.Contains(out.String(), `required flag(s) "key" not set`) {
 		t.Errorf("Expected error message for missing --key, got: %s", out.String())
 	}
 }
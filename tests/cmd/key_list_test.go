@@ -34,7 +34,7 @@ func TestKeyList_MissingAPIKey(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected error due to missing API key, got none")
 	}
-	if !strings.Contains(out.String(), "API URL and API Key are required") {
+	if !strings.Contains(out.String(), "API key is required") {
 		t.Errorf("Expected error message for missing API key, got: %s", out.String())
 	}
 }
@@ -49,7 +49,7 @@ func TestKeyList_InvalidURL(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Expected error due to invalid URL, got none")
 	}
-	if !strings.Contains(out.String(), "Request failed:") && !strings.Contains(out.String(), "API error:") {
+	if !strings.Contains(out.String(), "Error listing keys:") {
 		t.Errorf("Expected error message for invalid URL, got: %s", out.String())
 	}
 }
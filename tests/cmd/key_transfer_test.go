@@ -0,0 +1,38 @@
+// tests/cmd/key_transfer_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestKeyTransfer_FromUserOnlyMovesTheUsersKeys(t *testing.T) {
+	// The server ignores the user_id filter and lists every key
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/key/list":
+			return http.StatusOK, `{"keys": [
+				{"token": "hash-laptop", "key_alias": "jdoe-laptop", "user_id": "user_123"},
+				{"token": "hash-other", "key_alias": "asmith-laptop", "user_id": "user_456"},
+				{"token": "hash-team", "key_alias": "ci-bot", "team_id": "team-chat"}
+			], "total_count": 3, "current_page": 1, "total_pages": 1}`
+		case "/key/update":
+			return http.StatusOK, `{}`
+		}
+		return 0, ""
+	})
+
+	stdout, stderr, err := runCLI(t, proxy, "key", "transfer", "--from-user", "user_123", "--to-user", "user_789", "--yes")
+	if err != nil {
+		t.Fatalf("key transfer failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stdout, "The following 1 keys will be moved") {
+		t.Errorf("Expected a single key in the plan, got:\n%s", stdout)
+	}
+	updates := proxy.received("/key/update")
+	if len(updates) != 1 || updates[0].Body["key"] != "hash-laptop" || updates[0].Body["user_id"] != "user_789" {
+		t.Errorf("Expected only hash-laptop to be moved, got %+v", updates)
+	}
+}
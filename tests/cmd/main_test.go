@@ -0,0 +1,109 @@
+// tests/cmd/main_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// binary is the navigatorctl binary built once for the tests that run it
+// against a fake proxy
+var binary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "navigatorctl-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binary = filepath.Join(dir, "navigatorctl")
+	build := exec.Command("go", "build", "-o", binary, ".")
+	build.Dir = "../.."
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "building navigatorctl: %v\n%s", err, out)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// request is a request received by a fakeProxy
+type request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]interface{}
+}
+
+// fakeProxy serves canned JSON responses by path and records every request
+type fakeProxy struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+}
+
+// newFakeProxy starts a proxy answering each path with handle. handle returns
+// the status and the JSON body; an unexpected path fails the test.
+func newFakeProxy(t *testing.T, handle func(r request) (int, string)) *fakeProxy {
+	t.Helper()
+	proxy := &fakeProxy{}
+	proxy.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		json.NewDecoder(r.Body).Decode(&recorded.Body)
+		proxy.mu.Lock()
+		proxy.requests = append(proxy.requests, recorded)
+		proxy.mu.Unlock()
+
+		status, body := handle(recorded)
+		if status == 0 {
+			t.Errorf("Unexpected request %s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+			status, body = http.StatusNotFound, `{"detail": "not found"}`
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+// received returns the recorded requests to path
+func (p *fakeProxy) received(path string) []request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var result []request
+	for _, r := range p.requests {
+		if r.Path == path {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// runCLI runs navigatorctl against proxy in an empty working directory and
+// home, so no config file is picked up, and returns stdout and stderr
+func runCLI(t *testing.T, proxy *fakeProxy, args ...string) (string, string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	args = append(args, "--api-url", proxy.URL, "--api-key", "sk-test", "--retries", "0")
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "HOME="+dir)
+	cmd.Stdin = strings.NewReader("")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,122 @@
+// tests/cmd/team_clone_test.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newCloneProxy serves the CHAT team with two members and three keys
+func newCloneProxy(t *testing.T) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/team/list":
+			return http.StatusOK, `[{"team_id": "team-chat", "team_alias": "CHAT"}, {"team_id": "team-new", "team_alias": "CHAT-2"}]`
+		case "/team/info":
+			return http.StatusOK, `{"team_id": "team-chat", "team_info": {
+				"team_id": "team-chat", "team_alias": "CHAT", "models": ["gpt-4.1", "gpt-4.1-mini"],
+				"max_budget": 500, "budget_duration": "30d", "tpm_limit": 1000, "rpm_limit": 10,
+				"metadata": {"owner": "platform", "managed_by": "navigatorctl"},
+				"members_with_roles": [{"user_id": "user_1", "role": "admin"}, {"user_id": "user_2", "role": "user"}]},
+				"keys": [
+					{"token": "hash-1", "key_alias": "CHAT-ci-bot", "models": ["gpt-4.1"], "max_budget": 20},
+					{"token": "hash-2", "key_alias": "reporting"},
+					{"token": "hash-3"}]}`
+		case "/team/new":
+			return http.StatusOK, `{"team_id": "team-new", "team_alias": "CHAT-2"}`
+		case "/team/member_add":
+			return http.StatusOK, `{}`
+		case "/key/generate":
+			alias, _ := r.Body["key_alias"].(string)
+			return http.StatusOK, `{"key": "sk-` + alias + `", "token_id": "hash-` + alias + `", "key_alias": "` + alias + `"}`
+		}
+		return 0, ""
+	})
+}
+
+func TestTeamClone_KeyAliases(t *testing.T) {
+	proxy := newCloneProxy(t)
+
+	stdout, stderr, err := runCLI(t, proxy, "team", "clone", "--from", "CHAT", "--alias", "CHAT-2", "--with-keys", "--dry-run", "--output", "json")
+	if err != nil {
+		t.Fatalf("team clone failed: %v\n%s", err, stderr)
+	}
+
+	var result struct {
+		Keys []struct {
+			SourceAlias string `json:"source_alias"`
+			KeyAlias    string `json:"key_alias"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+	// The team alias is replaced, appended, or used to number unaliased keys
+	want := []string{"CHAT-2-ci-bot", "reporting-CHAT-2", "CHAT-2-key-3"}
+	if len(result.Keys) != len(want) {
+		t.Fatalf("Expected %d keys, got %+v", len(want), result.Keys)
+	}
+	for i, alias := range want {
+		if result.Keys[i].KeyAlias != alias {
+			t.Errorf("Key %d: expected alias %s, got %s", i, alias, result.Keys[i].KeyAlias)
+		}
+	}
+
+	for _, path := range []string{"/team/new", "/team/member_add", "/key/generate"} {
+		if got := proxy.received(path); len(got) != 0 {
+			t.Errorf("Expected no %s request on a dry run, got %+v", path, got)
+		}
+	}
+}
+
+func TestTeamClone_CopiesSettingsMembersAndKeys(t *testing.T) {
+	proxy := newCloneProxy(t)
+
+	stdout, stderr, err := runCLI(t, proxy, "team", "clone", "--from", "CHAT", "--alias", "CHAT-2", "--with-members", "--with-keys", "--yes")
+	if err != nil {
+		t.Fatalf("team clone failed: %v\n%s", err, stderr)
+	}
+
+	creates := proxy.received("/team/new")
+	if len(creates) != 1 {
+		t.Fatalf("Expected one team to be created, got %+v", creates)
+	}
+	team := creates[0].Body
+	if team["team_alias"] != "CHAT-2" || team["max_budget"] != 500.0 || team["budget_duration"] != "30d" || team["tpm_limit"] != 1000.0 || team["rpm_limit"] != 10.0 {
+		t.Errorf("Expected the source settings to be copied, got %v", team)
+	}
+	if models, _ := team["models"].([]interface{}); len(models) != 2 {
+		t.Errorf("Expected the source models, got %v", team["models"])
+	}
+	metadata, _ := team["metadata"].(map[string]interface{})
+	if metadata["owner"] != "platform" {
+		t.Errorf("Expected the source metadata, got %v", metadata)
+	}
+	if _, ok := metadata["managed_by"]; ok {
+		t.Errorf("Expected managed_by to be dropped, got %v", metadata)
+	}
+
+	adds := proxy.received("/team/member_add")
+	if len(adds) != 2 {
+		t.Fatalf("Expected two members to be added, got %+v", adds)
+	}
+	for _, add := range adds {
+		if add.Body["team_id"] != "team-new" {
+			t.Errorf("Expected members to be added to the new team, got %v", add.Body)
+		}
+	}
+
+	keys := proxy.received("/key/generate")
+	if len(keys) != 3 {
+		t.Fatalf("Expected three keys to be generated, got %+v", keys)
+	}
+	if first := keys[0].Body; first["key_alias"] != "CHAT-2-ci-bot" || first["team_id"] != "team-new" || first["max_budget"] != 20.0 {
+		t.Errorf("Expected the first key to mirror CHAT-ci-bot, got %v", first)
+	}
+	if !strings.Contains(stdout, "Key CHAT-2-ci-bot: sk-CHAT-2-ci-bot") {
+		t.Errorf("Expected the new secrets on stdout, got:\n%s", stdout)
+	}
+}
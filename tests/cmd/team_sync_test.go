@@ -0,0 +1,62 @@
+// tests/cmd/team_sync_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTeamSyncMembers_FailedLookupSkipsMember(t *testing.T) {
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/team/list":
+			return http.StatusOK, `[{"team_id": "team-chat", "team_alias": "CHAT"}, {"team_id": "team-cline", "team_alias": "CLINE"}]`
+		case "/team/info":
+			if r.Query == "team_id=team-chat" {
+				// user_keep has no email, so it could be the broken entry
+				return http.StatusOK, `{"team_id": "team-chat", "team_info": {"team_id": "team-chat", "members_with_roles": [{"user_id": "user_keep", "role": "user"}]}}`
+			}
+			return http.StatusOK, `{"team_id": "team-cline", "team_info": {"team_id": "team-cline", "members_with_roles": [{"user_id": "user_old", "role": "user"}]}}`
+		case "/user/info":
+			switch {
+			case strings.Contains(r.Query, "broken"):
+				return http.StatusInternalServerError, `{"detail": "database unavailable"}`
+			case strings.Contains(r.Query, "ok"):
+				return http.StatusOK, `{"user_id": "user_ok", "user_info": {"user_id": "user_ok", "user_email": "ok@example.com"}}`
+			}
+			return http.StatusNotFound, `{"detail": "user not found"}`
+		case "/user/list":
+			return http.StatusOK, `{"users": [], "total": 0, "page": 1, "page_size": 100, "total_pages": 1}`
+		case "/team/member_add", "/team/member_delete":
+			return http.StatusOK, `{}`
+		}
+		return 0, ""
+	})
+
+	file := filepath.Join(t.TempDir(), "roster.csv")
+	roster := "team,email,user_id,role\nCHAT,ok@example.com,,user\nCHAT,broken@example.com,,user\nCLINE,new@example.com,,user\n"
+	if err := os.WriteFile(file, []byte(roster), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := runCLI(t, proxy, "team", "sync-members", "--file", file, "--apply")
+	if err == nil {
+		t.Fatal("Expected sync-members to exit with an error")
+	}
+	if !strings.Contains(stderr, "1 failures:") || !strings.Contains(stderr, "CHAT: skipped broken@example.com") {
+		t.Errorf("Expected the skipped member in the failures, got:\n%s", stderr)
+	}
+
+	adds := proxy.received("/team/member_add")
+	if len(adds) != 2 || adds[0].Body["team_id"] != "team-chat" || adds[1].Body["team_id"] != "team-cline" {
+		t.Errorf("Expected adds to both teams, got %+v", adds)
+	}
+	removes := proxy.received("/team/member_delete")
+	if len(removes) != 1 || removes[0].Body["team_id"] != "team-cline" {
+		t.Errorf("Expected only user_old to be removed from CLINE, got %+v", removes)
+	}
+}
@@ -0,0 +1,68 @@
+// tests/cmd/team_update_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTeamUpdateProxy serves team CHAT with two models and two metadata entries
+func newTeamUpdateProxy(t *testing.T) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/team/list":
+			return http.StatusOK, `[{"team_id": "team-chat", "team_alias": "CHAT"}]`
+		case "/team/info":
+			return http.StatusOK, `{"team_id": "team-chat", "team_info": {"team_id": "team-chat", "team_alias": "CHAT", "models": ["gpt-4.1", "gpt-4.1-mini"], "metadata": {"owner": "platform", "cost_center": "42"}}}`
+		case "/team/update":
+			return http.StatusOK, `{"data": {"team_id": "team-chat", "team_alias": "CHAT"}}`
+		}
+		return 0, ""
+	})
+}
+
+func TestTeamUpdate_MergesModelsAndMetadata(t *testing.T) {
+	proxy := newTeamUpdateProxy(t)
+
+	_, stderr, err := runCLI(t, proxy, "team", "update", "--team-alias", "CHAT",
+		"--add-model", "o3", "--remove-model", "gpt-4.1-mini",
+		"--metadata", "env=prod", "--remove-metadata", "cost_center")
+	if err != nil {
+		t.Fatalf("team update failed: %v\n%s", err, stderr)
+	}
+
+	updates := proxy.received("/team/update")
+	if len(updates) != 1 {
+		t.Fatalf("Expected one update, got %+v", updates)
+	}
+	if models := updates[0].Body["models"]; !reflect.DeepEqual(models, []interface{}{"gpt-4.1", "o3"}) {
+		t.Errorf("Expected the edited model list, got %v", models)
+	}
+	if metadata := updates[0].Body["metadata"]; !reflect.DeepEqual(metadata, map[string]interface{}{"owner": "platform", "env": "prod"}) {
+		t.Errorf("Expected the merged metadata, got %v", metadata)
+	}
+}
+
+func TestTeamUpdate_RejectsEmptyUpdates(t *testing.T) {
+	proxy := newTeamUpdateProxy(t)
+
+	for _, args := range [][]string{
+		{},
+		{"--replace-metadata"},
+	} {
+		args = append([]string{"team", "update", "--team-alias", "CHAT"}, args...)
+		_, stderr, err := runCLI(t, proxy, args...)
+		if err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+		if !strings.Contains(stderr, "nothing to update") && !strings.Contains(stderr, "--replace-metadata requires --metadata") {
+			t.Errorf("%v: unexpected error:\n%s", args, stderr)
+		}
+	}
+	if updates := proxy.received("/team/update"); len(updates) != 0 {
+		t.Errorf("Expected no update, got %+v", updates)
+	}
+}
@@ -0,0 +1,112 @@
+// tests/cmd/user_offboard_test.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newOffboardProxy serves user_123 with one key and one team
+func newOffboardProxy(t *testing.T) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			return http.StatusOK, `{"user_id": "user_123", "user_info": {"user_id": "user_123", "user_email": "jdoe@example.com", "teams": ["team-chat"]}}`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [{"token": "hash-laptop", "key_name": "sk-...abcd", "key_alias": "jdoe-laptop", "user_id": "user_123"}], "total_count": 1, "current_page": 1, "total_pages": 1}`
+		case "/team/list":
+			return http.StatusOK, `[{"team_id": "team-chat", "team_alias": "CHAT"}]`
+		case "/key/block", "/team/member_delete":
+			return http.StatusOK, `{}`
+		}
+		return 0, ""
+	})
+}
+
+func TestUserOffboard_ReportIsPrivate(t *testing.T) {
+	proxy := newOffboardProxy(t)
+	file := filepath.Join(t.TempDir(), "offboard.json")
+
+	_, stderr, err := runCLI(t, proxy, "user", "offboard", "--user-id", "user_123", "--yes", "--report", file)
+	if err != nil {
+		t.Fatalf("user offboard failed: %v\n%s", err, stderr)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	var report struct {
+		Actions []struct {
+			Action string `json:"action"`
+			Status string `json:"status"`
+		} `json:"actions"`
+	}
+	data, _ := os.ReadFile(file)
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Expected a JSON report, got %q: %v", data, err)
+	}
+	if len(report.Actions) != 2 || report.Actions[0].Status != "done" || report.Actions[1].Status != "done" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestUserOffboard_ExistingReportChangesNothing(t *testing.T) {
+	proxy := newOffboardProxy(t)
+	file := filepath.Join(t.TempDir(), "offboard.json")
+	if err := os.WriteFile(file, []byte("previous report\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := runCLI(t, proxy, "user", "offboard", "--user-id", "user_123", "--yes", "--report", file)
+	if err == nil {
+		t.Fatal("Expected user offboard to refuse an existing report file")
+	}
+	if !strings.Contains(stderr, "already exists") {
+		t.Errorf("Expected the existing report to be reported, got:\n%s", stderr)
+	}
+	if len(proxy.received("/key/block"))+len(proxy.received("/team/member_delete")) != 0 {
+		t.Error("Expected nothing to change")
+	}
+	if data, _ := os.ReadFile(file); string(data) != "previous report\n" {
+		t.Errorf("Expected the existing report to be unchanged, got %q", data)
+	}
+}
+
+func TestUserOffboard_OnlyTouchesTheUsersKeys(t *testing.T) {
+	// The server ignores the user_id filter and lists every key
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			return http.StatusOK, `{"user_id": "user_123", "user_info": {"user_id": "user_123", "user_email": "jdoe@example.com"}}`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [
+				{"token": "hash-laptop", "key_alias": "jdoe-laptop", "user_id": "user_123"},
+				{"token": "hash-other", "key_alias": "asmith-laptop", "user_id": "user_456"},
+				{"token": "hash-team", "key_alias": "ci-bot", "team_id": "team-chat"}
+			], "total_count": 3, "current_page": 1, "total_pages": 1}`
+		case "/key/delete":
+			return http.StatusOK, `{"deleted_keys": ["hash-laptop"]}`
+		}
+		return 0, ""
+	})
+
+	_, stderr, err := runCLI(t, proxy, "user", "offboard", "--user-id", "user_123", "--keys", "delete", "--yes")
+	if err != nil {
+		t.Fatalf("user offboard failed: %v\n%s", err, stderr)
+	}
+	deletes := proxy.received("/key/delete")
+	if len(deletes) != 1 {
+		t.Fatalf("Expected only the user's key to be deleted, got %+v", deletes)
+	}
+	if keys, _ := deletes[0].Body["keys"].([]interface{}); len(keys) != 1 || keys[0] != "hash-laptop" {
+		t.Errorf("Expected hash-laptop to be deleted, got %v", deletes[0].Body)
+	}
+}
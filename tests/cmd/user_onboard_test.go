@@ -0,0 +1,224 @@
+// tests/cmd/user_onboard_test.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const onboardTeams = `[{"team_id": "team-chat", "team_alias": "CHAT"}, {"team_id": "team-cline", "team_alias": "CLINE"}]`
+
+// newOnboardProxy serves a proxy where jdoe@example.com does not exist yet.
+// failTeam, when set, answers /team/info for that team with a server error.
+func newOnboardProxy(t *testing.T, failTeam string) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			return http.StatusNotFound, `{"detail": "user not found"}`
+		case "/user/list":
+			return http.StatusOK, `{"users": [], "total": 0, "page": 1, "page_size": 100, "total_pages": 1}`
+		case "/user/new":
+			return http.StatusOK, `{"user_id": "user_new", "user_email": "jdoe@example.com"}`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [], "total_count": 0, "current_page": 1, "total_pages": 1}`
+		case "/team/list":
+			return http.StatusOK, onboardTeams
+		case "/team/info":
+			if failTeam != "" && strings.Contains(r.Query, failTeam) {
+				return http.StatusInternalServerError, `{"detail": "boom"}`
+			}
+			teamID := strings.TrimPrefix(r.Query, "team_id=")
+			alias := strings.ToUpper(strings.TrimPrefix(teamID, "team-"))
+			return http.StatusOK, `{"team_id": "` + teamID + `", "team_info": {"team_id": "` + teamID + `", "team_alias": "` + alias + `", "members_with_roles": []}, "keys": []}`
+		case "/team/member_add":
+			return http.StatusOK, `{}`
+		case "/key/generate":
+			alias, _ := r.Body["key_alias"].(string)
+			return http.StatusOK, `{"key": "sk-secret-` + alias + `", "token_id": "hash-` + alias + `", "key_alias": "` + alias + `"}`
+		}
+		return 0, ""
+	})
+}
+
+func TestUserOnboard_ReRunChangesNothing(t *testing.T) {
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			return http.StatusOK, `{"user_id": "user_123", "user_info": {"user_id": "user_123", "user_email": "jdoe@example.com"}}`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [{"token": "hash-existing", "key_alias": "jdoe-CHAT", "team_id": "team-chat", "user_id": "user_123"}], "total_count": 1, "current_page": 1, "total_pages": 1}`
+		case "/team/list":
+			return http.StatusOK, onboardTeams
+		case "/team/info":
+			return http.StatusOK, `{"team_id": "team-chat", "team_info": {"team_id": "team-chat", "team_alias": "CHAT", "members_with_roles": [{"user_id": "user_123", "role": "user"}]}, "keys": []}`
+		}
+		return 0, ""
+	})
+
+	stdout, stderr, err := runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--issue-keys")
+	if err != nil {
+		t.Fatalf("user onboard failed: %v\n%s", err, stderr)
+	}
+
+	var bundle struct {
+		UserID string `json:"user_id"`
+		Keys   []struct {
+			KeyAlias string `json:"key_alias"`
+			Token    string `json:"token"`
+			Secret   string `json:"secret"`
+			Existing bool   `json:"existing"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &bundle); err != nil {
+		t.Fatalf("Expected a JSON bundle, got %q: %v", stdout, err)
+	}
+	if bundle.UserID != "user_123" || len(bundle.Keys) != 1 {
+		t.Fatalf("Unexpected bundle: %+v", bundle)
+	}
+	if key := bundle.Keys[0]; !key.Existing || key.Secret != "" || key.Token != "hash-existing" {
+		t.Errorf("Expected the existing key without a secret, got %+v", key)
+	}
+	for _, path := range []string{"/user/new", "/team/member_add", "/key/generate"} {
+		if got := proxy.received(path); len(got) != 0 {
+			t.Errorf("Expected no %s request on a re-run, got %+v", path, got)
+		}
+	}
+}
+
+func TestUserOnboard_Bundles(t *testing.T) {
+	proxy := newOnboardProxy(t, "")
+
+	stdout, stderr, err := runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--team", "CLINE", "--issue-keys", "--bundle-format", "env")
+	if err != nil {
+		t.Fatalf("user onboard failed: %v\n%s", err, stderr)
+	}
+	for _, want := range []string{
+		"# Credentials for jdoe@example.com (user_new)\n",
+		"LITELLM_BASE_URL=" + proxy.URL + "\n",
+		"LITELLM_API_KEY_CHAT=sk-secret-jdoe-CHAT\n",
+		"LITELLM_API_KEY_CLINE=sk-secret-jdoe-CLINE\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in the env bundle:\n%s", want, stdout)
+		}
+	}
+
+	adds := proxy.received("/team/member_add")
+	if len(adds) != 2 || adds[0].Body["team_id"] != "team-chat" || adds[1].Body["team_id"] != "team-cline" {
+		t.Errorf("Expected the user to be added to both teams, got %+v", adds)
+	}
+
+	stdout, stderr, err = runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--issue-keys")
+	if err != nil {
+		t.Fatalf("user onboard failed: %v\n%s", err, stderr)
+	}
+	var bundle struct {
+		BaseURL string   `json:"base_url"`
+		Teams   []string `json:"teams"`
+		Keys    []struct {
+			Team   string `json:"team"`
+			Token  string `json:"token"`
+			Secret string `json:"secret"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &bundle); err != nil {
+		t.Fatalf("Expected a JSON bundle, got %q: %v", stdout, err)
+	}
+	if bundle.BaseURL != proxy.URL || len(bundle.Teams) != 1 || len(bundle.Keys) != 1 {
+		t.Fatalf("Unexpected bundle: %+v", bundle)
+	}
+	if key := bundle.Keys[0]; key.Team != "CHAT" || key.Secret != "sk-secret-jdoe-CHAT" || key.Token != "hash-jdoe-CHAT" {
+		t.Errorf("Unexpected key in the bundle: %+v", key)
+	}
+}
+
+func TestUserOnboard_FailurePrintsIssuedSecrets(t *testing.T) {
+	proxy := newOnboardProxy(t, "team-cline")
+
+	stdout, stderr, err := runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--team", "CLINE", "--issue-keys", "--bundle-format", "env")
+	if err == nil {
+		t.Fatal("Expected user onboard to fail")
+	}
+	if !strings.Contains(stdout, "LITELLM_API_KEY_CHAT=sk-secret-jdoe-CHAT") {
+		t.Errorf("Expected the secret issued before the failure on stdout, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Error getting team CLINE") {
+		t.Errorf("Expected the failure on stderr, got %q", stderr)
+	}
+}
+
+func TestUserOnboard_BundleFileIsPrivate(t *testing.T) {
+	proxy := newOnboardProxy(t, "")
+	file := filepath.Join(t.TempDir(), "jdoe.env")
+
+	stdout, stderr, err := runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--issue-keys", "--bundle-format", "env", "--bundle-file", file)
+	if err != nil {
+		t.Fatalf("user onboard failed: %v\n%s", err, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("Expected nothing on stdout, got %q", stdout)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(file); !strings.Contains(string(data), "LITELLM_API_KEY_CHAT=sk-secret-jdoe-CHAT\n") {
+		t.Errorf("Expected the secret in the bundle file, got %q", data)
+	}
+
+	// An existing file, possibly readable by others, is not reused; the
+	// bundle goes to stderr instead so the secret is not lost
+	shared := filepath.Join(t.TempDir(), "shared.env")
+	if err := os.WriteFile(shared, []byte("# shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr, err = runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CLINE", "--issue-keys", "--bundle-format", "env", "--bundle-file", shared)
+	if err == nil {
+		t.Fatal("Expected user onboard to refuse an existing bundle file")
+	}
+	if !strings.Contains(stderr, "Error writing credentials bundle") || !strings.Contains(stderr, "LITELLM_API_KEY_CLINE=sk-secret-jdoe-CLINE") {
+		t.Errorf("Expected the write error and the bundle on stderr, got:\n%s", stderr)
+	}
+	if data, _ := os.ReadFile(shared); string(data) != "# shared\n" {
+		t.Errorf("Expected the existing file to be unchanged, got %q", data)
+	}
+}
+
+func TestUserOnboard_IgnoresOtherUsersKeys(t *testing.T) {
+	// The server ignores the user_id filter; the matching key belongs to
+	// someone else, so a key must still be issued
+	proxy := newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			return http.StatusOK, `{"user_id": "user_123", "user_info": {"user_id": "user_123", "user_email": "jdoe@example.com"}}`
+		case "/key/list":
+			return http.StatusOK, `{"keys": [{"token": "hash-other", "key_alias": "jdoe-CHAT", "team_id": "team-chat", "user_id": "user_456"}], "total_count": 1, "current_page": 1, "total_pages": 1}`
+		case "/team/list":
+			return http.StatusOK, onboardTeams
+		case "/team/info":
+			return http.StatusOK, `{"team_id": "team-chat", "team_info": {"team_id": "team-chat", "team_alias": "CHAT", "members_with_roles": [{"user_id": "user_123", "role": "user"}]}, "keys": []}`
+		case "/key/generate":
+			return http.StatusOK, `{"key": "sk-secret-jdoe-CHAT", "token_id": "hash-jdoe-CHAT", "key_alias": "jdoe-CHAT"}`
+		}
+		return 0, ""
+	})
+
+	stdout, stderr, err := runCLI(t, proxy, "user", "onboard", "--email", "jdoe@example.com", "--team", "CHAT", "--issue-keys", "--bundle-format", "env")
+	if err != nil {
+		t.Fatalf("user onboard failed: %v\n%s", err, stderr)
+	}
+	if generates := proxy.received("/key/generate"); len(generates) != 1 || generates[0].Body["user_id"] != "user_123" {
+		t.Errorf("Expected a key to be issued for user_123, got %+v", generates)
+	}
+	if !strings.Contains(stdout, "LITELLM_API_KEY_CHAT=sk-secret-jdoe-CHAT\n") {
+		t.Errorf("Expected the new secret in the bundle, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,91 @@
+// tests/cmd/user_test.go
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+)
+
+// emailIgnoringProxy answers /user/info for the caller whatever the email,
+// as servers that do not look users up by email do
+func emailIgnoringProxy(t *testing.T) *fakeProxy {
+	return newFakeProxy(t, func(r request) (int, string) {
+		switch r.Path {
+		case "/user/info":
+			if r.Query == "user_id=user_123" {
+				return http.StatusOK, `{"user_id": "user_123", "user_info": {"user_id": "user_123", "user_email": "jdoe@example.com", "user_role": "internal_user"}}`
+			}
+			return http.StatusOK, `{"user_id": "admin", "user_info": {"user_id": "admin", "user_email": "admin@example.com", "user_role": "proxy_admin"}}`
+		case "/user/list":
+			return http.StatusOK, `{"users": [{"user_id": "user_123", "user_email": "jdoe@example.com"}], "total": 1, "page": 1, "page_size": 100, "total_pages": 1}`
+		case "/user/delete", "/user/update":
+			return http.StatusOK, `{}`
+		}
+		return 0, ""
+	})
+}
+
+func TestUserDelete_ResolvesEmailToTheMatchingUser(t *testing.T) {
+	proxy := emailIgnoringProxy(t)
+
+	if _, stderr, err := runCLI(t, proxy, "user", "delete", "--email", "jdoe@example.com", "--yes"); err != nil {
+		t.Fatalf("user delete failed: %v\n%s", err, stderr)
+	}
+
+	deletes := proxy.received("/user/delete")
+	if len(deletes) != 1 {
+		t.Fatalf("Expected one delete, got %+v", deletes)
+	}
+	ids, _ := deletes[0].Body["user_ids"].([]interface{})
+	if len(ids) != 1 || ids[0] != "user_123" {
+		t.Errorf("Expected user_123 to be deleted, got %v", deletes[0].Body)
+	}
+}
+
+func TestUserDelete_UnknownEmailDeletesNothing(t *testing.T) {
+	proxy := emailIgnoringProxy(t)
+
+	if _, _, err := runCLI(t, proxy, "user", "delete", "--email", "nobody@example.com", "--yes"); err == nil {
+		t.Fatal("Expected user delete to fail for an unknown email")
+	}
+	if deletes := proxy.received("/user/delete"); len(deletes) != 0 {
+		t.Errorf("Expected no delete, got %+v", deletes)
+	}
+}
+
+func TestUserUpdate_ResolvesEmailToTheMatchingUser(t *testing.T) {
+	proxy := emailIgnoringProxy(t)
+
+	if _, stderr, err := runCLI(t, proxy, "user", "update", "--email", "jdoe@example.com", "--max-budget", "50"); err != nil {
+		t.Fatalf("user update failed: %v\n%s", err, stderr)
+	}
+
+	updates := proxy.received("/user/update")
+	if len(updates) != 1 || updates[0].Body["user_id"] != "user_123" {
+		t.Errorf("Expected user_123 to be updated, got %+v", updates)
+	}
+}
+
+func TestUserReadCommands_ResolveEmailToTheMatchingUser(t *testing.T) {
+	proxy := emailIgnoringProxy(t)
+
+	for _, command := range []string{"info", "keys", "teams"} {
+		if _, stderr, err := runCLI(t, proxy, "user", command, "--email", "jdoe@example.com", "--output", "json"); err != nil {
+			t.Fatalf("user %s failed: %v\n%s", command, err, stderr)
+		}
+	}
+
+	reads := 0
+	for _, info := range proxy.received("/user/info") {
+		if info.Query == "user_id=user_123" {
+			reads++
+		}
+	}
+	if reads != 3 {
+		t.Errorf("Expected each command to read user_123, got %+v", proxy.received("/user/info"))
+	}
+	if _, _, err := runCLI(t, proxy, "user", "keys", "--email", "nobody@example.com"); err == nil {
+		t.Error("Expected user keys to fail for an unknown email")
+	}
+}
@@ -0,0 +1,188 @@
+// tests/config/config_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncecere/navigatorctl/pkg/config"
+	"github.com/spf13/viper"
+)
+
+const contextsConfig = `# shared settings
+api:
+  url: https://default.example.com
+  key: sk-default
+  timeout: 10s
+current-context: dev # switched by use-context
+contexts:
+  dev:
+    api:
+      url: https://dev.example.com
+      key: sk-dev
+  prod:
+    api:
+      url: https://prod.example.com
+  empty:
+`
+
+func readConfig(t *testing.T, content string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatalf("ReadConfig failed: %v", err)
+	}
+	return v
+}
+
+func TestUseContext_OverridesTopLevelSettings(t *testing.T) {
+	v := readConfig(t, contextsConfig)
+
+	if err := config.UseContext(v, "PROD"); err != nil {
+		t.Fatalf("UseContext failed: %v", err)
+	}
+	if got := v.GetString("api.url"); got != "https://prod.example.com" {
+		t.Errorf("Expected the context's URL, got %s", got)
+	}
+	// Settings the context leaves out keep their top-level values
+	if got := v.GetString("api.key"); got != "sk-default" {
+		t.Errorf("Expected the top-level key, got %s", got)
+	}
+	if got := v.GetDuration("api.timeout").String(); got != "10s" {
+		t.Errorf("Expected the top-level timeout, got %s", got)
+	}
+
+	if err := config.UseContext(v, "empty"); err != nil {
+		t.Errorf("Expected a context without settings to be usable, got %v", err)
+	}
+
+	err := config.UseContext(v, "staging")
+	if err == nil || !strings.Contains(err.Error(), "available: dev, empty, prod") {
+		t.Errorf("Expected an error listing the contexts, got %v", err)
+	}
+}
+
+func TestContexts_SortedWithCurrent(t *testing.T) {
+	v := readConfig(t, contextsConfig)
+
+	contexts := config.Contexts(v, "dev")
+	if len(contexts) != 3 {
+		t.Fatalf("Expected 3 contexts, got %+v", contexts)
+	}
+	want := []config.Context{
+		{Name: "dev", URL: "https://dev.example.com", Current: true},
+		{Name: "empty"},
+		{Name: "prod", URL: "https://prod.example.com"},
+	}
+	for i := range want {
+		if contexts[i] != want[i] {
+			t.Errorf("Context %d: expected %+v, got %+v", i, want[i], contexts[i])
+		}
+	}
+}
+
+func TestSetValue_KeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contextsConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.SetValue(path, []string{config.CurrentContextKey}, "prod"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := config.SetValue(path, []string{"retry", "max_retries"}, "5"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"# shared settings", "current-context: prod # switched by use-context", "retry:\n  max_retries: 5\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in:\n%s", want, content)
+		}
+	}
+
+	v := readConfig(t, content)
+	if v.GetInt("retry.max_retries") != 5 || v.GetString("contexts.dev.api.key") != "sk-dev" {
+		t.Errorf("Unexpected settings after SetValue:\n%s", content)
+	}
+
+	if err := config.SetValue(path, []string{"api"}, "x"); err == nil {
+		t.Error("Expected an error replacing a mapping with a value")
+	}
+}
+
+func TestSetValue_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.yaml")
+
+	if err := config.SetValue(path, []string{"api", "url"}, "https://example.com"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a private file, got %v", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "api:\n  url: https://example.com\n" {
+		t.Errorf("Unexpected content:\n%s", data)
+	}
+}
+
+func TestView_RedactsKeys(t *testing.T) {
+	v := readConfig(t, contextsConfig)
+	v.SetDefault("retry.max_backoff", 10*time.Second)
+
+	settings := config.View(v)
+	api := settings["api"].(map[string]interface{})
+	if api["key"] != config.Redacted || api["url"] != "https://default.example.com" {
+		t.Errorf("Expected only the key redacted, got %v", api)
+	}
+	dev := settings["contexts"].(map[string]interface{})["dev"].(map[string]interface{})["api"].(map[string]interface{})
+	if dev["key"] != config.Redacted {
+		t.Errorf("Expected context keys redacted, got %v", dev)
+	}
+	if got := settings["retry"].(map[string]interface{})["max_backoff"]; got != "10s" {
+		t.Errorf("Expected durations as strings, got %v", got)
+	}
+
+	if key, ok := config.Get(v, "api.key"); !ok || key != "sk-default" {
+		t.Errorf("Expected Get to return the key, got %v", key)
+	}
+	if backoff, _ := config.Get(v, "retry.max_backoff"); backoff != "10s" {
+		t.Errorf("Expected Get to format durations, got %v", backoff)
+	}
+	if _, ok := config.Get(v, "team.id"); ok {
+		t.Error("Expected unset keys to be reported")
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://ai.example.com", true},
+		{"http://localhost:4000/", true},
+		{"ai.example.com", false},
+		{"ftp://ai.example.com", false},
+		{"https://", false},
+		{"https://ai.example.com/?team=x", false},
+	}
+
+	for _, tt := range tests {
+		if err := config.ValidateURL(tt.url); (err == nil) != tt.valid {
+			t.Errorf("ValidateURL(%q) = %v, want valid %v", tt.url, err, tt.valid)
+		}
+	}
+}
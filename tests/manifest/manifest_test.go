@@ -0,0 +1,353 @@
+// tests/manifest/manifest_test.go
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/manifest"
+)
+
+const manifests = `apiVersion: navigatorctl/v1
+kind: Key
+metadata:
+  name: chat-bot
+spec:
+  team: CHAT
+  user: jdoe@example.com
+  max_budget: 25
+---
+apiVersion: navigatorctl/v1
+kind: TeamMembership
+metadata:
+  name: CHAT
+spec:
+  members:
+    - email: jdoe@example.com
+      role: admin
+---
+apiVersion: navigatorctl/v1
+kind: Team
+metadata:
+  name: CHAT
+spec:
+  models: [gpt-4.1]
+  max_budget: 500
+---
+apiVersion: navigatorctl/v1
+kind: User
+metadata:
+  name: jdoe@example.com
+spec:
+  role: internal_user
+---
+`
+
+func parse(t *testing.T, input string) *manifest.Set {
+	t.Helper()
+	set, err := manifest.Parse(strings.NewReader(input), "test.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return set
+}
+
+func TestParse_AllKinds(t *testing.T) {
+	set := parse(t, manifests)
+
+	if len(set.Users) != 1 || len(set.Teams) != 1 || len(set.Memberships) != 1 || len(set.Keys) != 1 {
+		t.Fatalf("Expected one manifest of each kind, got %+v", set)
+	}
+	if set.Keys[0].Source != "test.yaml:1" || set.Users[0].Source != "test.yaml:27" {
+		t.Errorf("Expected sources with line numbers, got %s and %s", set.Keys[0].Source, set.Users[0].Source)
+	}
+	if *set.Teams[0].Spec.MaxBudget != 500 {
+		t.Errorf("Expected team max budget 500, got %v", *set.Teams[0].Spec.MaxBudget)
+	}
+	if set.Memberships[0].Spec.Members[0].Role != "admin" {
+		t.Errorf("Unexpected members: %+v", set.Memberships[0].Spec.Members)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown kind":      "apiVersion: navigatorctl/v1\nkind: Model\nmetadata: {name: x}\n",
+		"wrong version":     "apiVersion: v2\nkind: Team\nmetadata: {name: x}\n",
+		"missing name":      "apiVersion: navigatorctl/v1\nkind: Team\n",
+		"unknown field":     "apiVersion: navigatorctl/v1\nkind: Team\nmetadata: {name: x}\nspec: {budget: 5}\n",
+		"invalid role":      "apiVersion: navigatorctl/v1\nkind: User\nmetadata: {name: x}\nspec: {role: owner}\n",
+		"duplicate team":    "apiVersion: navigatorctl/v1\nkind: Team\nmetadata: {name: x}\n---\napiVersion: navigatorctl/v1\nkind: Team\nmetadata: {name: x}\n",
+		"member without id": "apiVersion: navigatorctl/v1\nkind: TeamMembership\nmetadata: {name: x}\nspec: {members: [{role: user}]}\n",
+	}
+	for name, input := range tests {
+		if _, err := manifest.Parse(strings.NewReader(input), "test.yaml"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewPlan_CreatesInDependencyOrder(t *testing.T) {
+	set := parse(t, manifests)
+
+	plan, err := manifest.NewPlan(set, &manifest.State{}, manifest.PlanOptions{})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	var order []string
+	for _, action := range plan.Actions {
+		order = append(order, action.Op+" "+action.Kind)
+	}
+	expected := "create User,create Team,create TeamMembership,create Key"
+	if strings.Join(order, ",") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(order, ","))
+	}
+}
+
+func TestNewPlan_UpdatesOnlyManagedFields(t *testing.T) {
+	set := parse(t, manifests)
+	budget := 100.0
+	state := &manifest.State{
+		Users: []api.UserInfo{{UserID: "u1", UserEmail: "JDoe@example.com", UserRole: "internal_user",
+			Metadata: map[string]interface{}{"managed_by": "navigatorctl"}}},
+		Teams: []api.Team{{TeamID: "t1", TeamAlias: "CHAT", Models: []string{"gpt-4.1"}, MaxBudget: &budget,
+			TPMLimit: new(int64), Metadata: map[string]interface{}{"managed_by": "navigatorctl", "owner": "ops"}}},
+		Members: map[string][]api.TeamMember{"t1": {{UserID: "u1", Role: "admin"}, {UserID: "u2", Role: "user"}}},
+		Keys: []api.KeyObject{{Token: "hash1", KeyInfo: api.KeyInfo{KeyAlias: "chat-bot", TeamID: "t1", UserID: "u1",
+			MaxBudget: &budget, Metadata: map[string]interface{}{"managed_by": "navigatorctl"}}}},
+	}
+
+	plan, err := manifest.NewPlan(set, state, manifest.PlanOptions{})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if len(plan.Actions) != 2 {
+		t.Fatalf("Expected team and key budget updates, got %+v", plan.Actions)
+	}
+	team, key := plan.Actions[0], plan.Actions[1]
+	if team.Op != manifest.OpUpdate || team.Kind != manifest.KindTeam || team.ID != "t1" ||
+		len(team.Changes) != 1 || team.Changes[0].Field != "max_budget" {
+		t.Errorf("Expected only the team budget to change, got %+v", team)
+	}
+	if key.Kind != manifest.KindKey || key.ID != "hash1" || len(key.Changes) != 1 || key.Changes[0].Field != "max_budget" {
+		t.Errorf("Expected only the key budget to change, got %+v", key)
+	}
+
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Kind != manifest.KindTeamMembership || plan.Skipped[0].Op != manifest.OpDelete {
+		t.Errorf("Expected the extra member removal to be skipped, got %+v", plan.Skipped)
+	}
+}
+
+func TestNewPlan_PruneDeletesOnlyManagedObjects(t *testing.T) {
+	state := &manifest.State{
+		Users: []api.UserInfo{{UserID: "u1", Metadata: map[string]interface{}{"managed_by": "navigatorctl"}}},
+		Teams: []api.Team{
+			{TeamID: "t1", TeamAlias: "OLD", Metadata: map[string]interface{}{"managed_by": "navigatorctl"}},
+			{TeamID: "t2", TeamAlias: "MANUAL"},
+		},
+		Keys: []api.KeyObject{{Token: "hash1", KeyInfo: api.KeyInfo{KeyAlias: "old-key", Metadata: map[string]interface{}{"managed_by": "navigatorctl"}}}},
+	}
+
+	plan, err := manifest.NewPlan(&manifest.Set{}, state, manifest.PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	var order []string
+	for _, action := range plan.Actions {
+		order = append(order, action.Kind+" "+action.ID)
+	}
+	expected := "Key hash1,Team t1,User u1"
+	if strings.Join(order, ",") != expected {
+		t.Errorf("Expected deletions %s, got %s", expected, strings.Join(order, ","))
+	}
+}
+
+func TestNewPlan_UnknownTeam(t *testing.T) {
+	set := parse(t, "apiVersion: navigatorctl/v1\nkind: Key\nmetadata: {name: k}\nspec: {team: NOPE}\n")
+	if _, err := manifest.NewPlan(set, &manifest.State{}, manifest.PlanOptions{}); err == nil {
+		t.Error("Expected an error for a key in an unknown team")
+	}
+}
+
+func TestApply_CreatesKeyWithManagedMetadata(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team/list":
+			w.Write([]byte(`[{"team_id": "t1", "team_alias": "CHAT"}]`))
+		case "/key/generate":
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write([]byte(`{"key": "sk-new", "token": "hash2"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	set := parse(t, "apiVersion: navigatorctl/v1\nkind: Key\nmetadata: {name: k}\nspec: {team: CHAT, max_budget: 5}\n")
+	state := &manifest.State{Teams: []api.Team{{TeamID: "t1", TeamAlias: "CHAT"}}}
+	plan, err := manifest.NewPlan(set, state, manifest.PlanOptions{})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	secret, err := plan.Actions[0].Apply(context.Background(), api.NewClient(server.URL, "sk-test"))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if secret != "sk-new" {
+		t.Errorf("Expected the new secret, got %q", secret)
+	}
+	metadata, _ := body["metadata"].(map[string]interface{})
+	if body["team_id"] != "t1" || body["key_alias"] != "k" || metadata["managed_by"] != "navigatorctl" {
+		t.Errorf("Unexpected generate request: %v", body)
+	}
+}
+
+func exportState() *manifest.State {
+	budget := 100.0
+	return &manifest.State{
+		Users: []api.UserInfo{
+			{UserID: "u2", UserEmail: "zoe@example.com", UserRole: "internal_user"},
+			{UserID: "u1", UserEmail: "adam@example.com", UserRole: "proxy_admin"},
+		},
+		Teams: []api.Team{
+			{TeamID: "t2", TeamAlias: "Research", Models: []string{"b", "a"}},
+			{TeamID: "t1", TeamAlias: "CHAT", MaxBudget: &budget,
+				Metadata: map[string]interface{}{"owner": "ops", "webhook_secret": "s3cret"}},
+		},
+		Members: map[string][]api.TeamMember{
+			"t1": {{UserID: "u2", Role: "user"}, {UserID: "u1", Role: "admin"}},
+		},
+		Keys: []api.KeyObject{
+			{Token: "hash1", KeyInfo: api.KeyInfo{KeyAlias: "chat-bot", TeamID: "t1"}},
+			{Token: "hash2", KeyInfo: api.KeyInfo{KeyAlias: ""}},
+		},
+	}
+}
+
+func TestExport_StableAndRedacted(t *testing.T) {
+	files, warnings := manifest.Export(exportState())
+
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if strings.Join(names, ",") != "teams/chat.yaml,teams/research.yaml,users.yaml" {
+		t.Errorf("Unexpected files %v", names)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "no alias") {
+		t.Errorf("Expected a warning for the unaliased key, got %v", warnings)
+	}
+
+	data, err := files[0].Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	chat := string(data)
+	if strings.Contains(chat, "s3cret") || !strings.Contains(chat, "webhook_secret: <redacted>") {
+		t.Errorf("Expected the secret metadata to be redacted:\n%s", chat)
+	}
+	if strings.Index(chat, "user_id: u1") > strings.Index(chat, "user_id: u2") {
+		t.Errorf("Expected members sorted by user ID:\n%s", chat)
+	}
+	if !strings.Contains(chat, "email: zoe@example.com") || !strings.Contains(chat, "name: chat-bot") {
+		t.Errorf("Expected member emails and the team's key:\n%s", chat)
+	}
+
+	// Reordered live state exports identically
+	state := exportState()
+	state.Teams[0], state.Teams[1] = state.Teams[1], state.Teams[0]
+	state.Users[0], state.Users[1] = state.Users[1], state.Users[0]
+	again, _ := manifest.Export(state)
+	for i := range files {
+		a, _ := files[i].Bytes()
+		b, _ := again[i].Bytes()
+		if string(a) != string(b) {
+			t.Errorf("Export of %s is not stable:\n%s\n---\n%s", files[i].Name, a, b)
+		}
+	}
+}
+
+func TestExport_RoundTripsWithoutChanges(t *testing.T) {
+	state := exportState()
+	for i := range state.Teams {
+		state.Teams[i].Metadata = map[string]interface{}{"managed_by": "navigatorctl", "api_key": "sk-x"}
+	}
+	for i := range state.Users {
+		state.Users[i].Metadata = map[string]interface{}{"managed_by": "navigatorctl"}
+	}
+	state.Keys[0].Metadata = map[string]interface{}{"managed_by": "navigatorctl"}
+	state.Keys = state.Keys[:1]
+
+	files, _ := manifest.Export(state)
+	var stream []string
+	for _, file := range files {
+		data, err := file.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes failed: %v", err)
+		}
+		stream = append(stream, string(data))
+	}
+	set := parse(t, strings.Join(stream, "---\n"))
+
+	plan, err := manifest.NewPlan(set, state, manifest.PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	if len(plan.Actions) != 0 {
+		t.Errorf("Expected an exported state to plan no changes, got %+v", plan.Actions)
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	set := parse(t, manifests)
+	budget := 100.0
+	state := &manifest.State{
+		// Unmarked objects that match are in sync
+		Users: []api.UserInfo{{UserID: "u1", UserEmail: "jdoe@example.com", UserRole: "internal_user"}},
+		Teams: []api.Team{
+			{TeamID: "t1", TeamAlias: "CHAT", Models: []string{"gpt-4.1"}, MaxBudget: &budget},
+			{TeamID: "t2", TeamAlias: "OLD", Metadata: map[string]interface{}{"managed_by": "navigatorctl"}},
+		},
+		Members: map[string][]api.TeamMember{"t1": {{UserID: "u1", Role: "admin"}, {UserID: "u2", Role: "user"}}},
+	}
+
+	checks, err := manifest.DetectDrift(set, state)
+	if err != nil {
+		t.Fatalf("DetectDrift failed: %v", err)
+	}
+
+	statuses := make(map[string]manifest.Check)
+	for _, check := range checks {
+		statuses[check.Kind+" "+check.Name] = check
+	}
+	if len(checks) != 5 {
+		t.Errorf("Expected four manifests and one unexpected team, got %+v", checks)
+	}
+	if statuses["User jdoe@example.com"].Drifted() {
+		t.Errorf("Expected the user to be in sync, got %+v", statuses["User jdoe@example.com"])
+	}
+	team := statuses["Team CHAT"]
+	if team.Status != manifest.StatusChanged || len(team.Changes) != 1 || team.Changes[0].Field != "max_budget" {
+		t.Errorf("Expected the team budget to drift, got %+v", team)
+	}
+	members := statuses["TeamMembership CHAT"]
+	if members.Status != manifest.StatusChanged || len(members.Changes) != 1 || members.Changes[0].New != nil {
+		t.Errorf("Expected the extra member to drift, got %+v", members)
+	}
+	if statuses["Key chat-bot"].Status != manifest.StatusMissing {
+		t.Errorf("Expected the key to be missing, got %+v", statuses["Key chat-bot"])
+	}
+	if statuses["Team OLD"].Status != manifest.StatusUnexpected {
+		t.Errorf("Expected the undeclared managed team to be unexpected, got %+v", statuses["Team OLD"])
+	}
+}
@@ -0,0 +1,104 @@
+// tests/roster/roster_test.go
+
+package roster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncecere/navigatorctl/pkg/api"
+	"github.com/ncecere/navigatorctl/pkg/roster"
+)
+
+func TestParseCSV_GroupsByTeam(t *testing.T) {
+	input := `team,email,user_id,role
+# comments and column order are free
+CHAT,jdoe@example.com,,admin
+RESEARCH,,user_123,
+CHAT,,user_456,user
+`
+	r, err := roster.ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+
+	if len(r.Teams) != 2 || r.Teams[0].Team != "CHAT" || r.Teams[1].Team != "RESEARCH" {
+		t.Fatalf("Expected teams CHAT and RESEARCH in file order, got %+v", r.Teams)
+	}
+	if len(r.Teams[0].Members) != 2 || r.Teams[0].Members[0].Role != "admin" {
+		t.Errorf("Unexpected CHAT members: %+v", r.Teams[0].Members)
+	}
+	if r.Teams[1].Members[0].Role != roster.DefaultRole {
+		t.Errorf("Expected the default role, got %q", r.Teams[1].Members[0].Role)
+	}
+}
+
+func TestParseCSV_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing team column":  "email,role\njdoe@example.com,user\n",
+		"member without ID":    "team,email,user_id\nCHAT,,\n",
+		"invalid role":         "team,user_id,role\nCHAT,user_123,owner\n",
+		"duplicate member":     "team,email\nCHAT,jdoe@example.com\nCHAT,JDOE@example.com\n",
+		"missing user columns": "team,role\nCHAT,user\n",
+	}
+	for name, input := range tests {
+		if _, err := roster.ParseCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	input := `teams:
+  - team: CHAT
+    members:
+      - email: jdoe@example.com
+        role: admin
+      - user_id: user_123
+`
+	r, err := roster.ParseYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %v", err)
+	}
+	if len(r.Teams) != 1 || len(r.Teams[0].Members) != 2 || r.Teams[0].Members[1].Role != "user" {
+		t.Errorf("Unexpected roster: %+v", r)
+	}
+
+	if _, err := roster.ParseYAML(strings.NewReader("teams:\n  - team: CHAT\n    memebers: []\n")); err == nil {
+		t.Errorf("Expected unknown fields to be rejected")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	desired := []roster.Member{
+		{UserID: "alice", Role: "admin"},
+		{Email: "Bob@example.com", Role: "user"},
+		{UserID: "carol", Role: "user"},
+	}
+	current := []api.TeamMember{
+		{UserID: "alice", Role: "user"},
+		{UserID: "bob", UserEmail: "bob@example.com", Role: "user"},
+		{UserID: "dave", Role: "user"},
+	}
+
+	actions := roster.Plan("CHAT", desired, current, roster.PlanOptions{})
+	if len(actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %+v", actions)
+	}
+	if actions[0].Type != roster.ActionAdd || actions[0].Member.UserID != "carol" {
+		t.Errorf("Expected carol to be added first, got %+v", actions[0])
+	}
+	if actions[1].Type != roster.ActionUpdateRole || actions[1].Member.UserID != "alice" || actions[1].OldRole != "user" {
+		t.Errorf("Expected alice to be promoted, got %+v", actions[1])
+	}
+	if actions[2].Type != roster.ActionRemove || actions[2].Member.UserID != "dave" {
+		t.Errorf("Expected dave to be removed, got %+v", actions[2])
+	}
+
+	actions = roster.Plan("CHAT", desired, current, roster.PlanOptions{NoRemove: true})
+	for _, action := range actions {
+		if action.Type == roster.ActionRemove {
+			t.Errorf("Expected no removals with NoRemove, got %+v", action)
+		}
+	}
+}